@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package gocli
+
+import "io/fs"
+
+// read permission is taken from mode of file or directory
+func canRead(path string, info fs.FileInfo) bool {
+	return info.Mode().Perm()&0400 != 0
+}
+
+// write permission is taken from mode of file or directory, on Windows it reflects read-only attribute
+func canWrite(path string, info fs.FileInfo) bool {
+	return info.Mode().Perm()&0200 != 0
+}
@@ -0,0 +1,19 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package gocli
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/unix"
+)
+
+// checks read permission of process for existing file or directory without opening it, so FIFO does not block
+func canRead(path string, info fs.FileInfo) bool {
+	return unix.Access(path, unix.R_OK) == nil
+}
+
+// checks write permission of process for existing file or directory without opening it
+func canWrite(path string, info fs.FileInfo) bool {
+	return unix.Access(path, unix.W_OK) == nil
+}
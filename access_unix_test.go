@@ -0,0 +1,32 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package gocli
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestPathModeFifo(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "fifo")
+	if err := unix.Mkfifo(fifo, 0666); err != nil {
+		t.Skip(err)
+	}
+
+	// checks must not open FIFO, opening it blocks until other side is opened
+	done := make(chan error)
+	go func() {
+		done <- (&Flag[Path]{Name: "p", PathMode: PathReadable | PathWritable}).SetValue(fifo)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("SetValue() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetValue() blocked on FIFO")
+	}
+}
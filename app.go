@@ -1,13 +1,16 @@
 package gocli
 
 import (
-	"bufio"
 	"bytes"
+	gocontext "context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"unicode/utf8"
 
 	"github.com/ez-leka/gocli/i18n"
@@ -35,16 +38,35 @@ type Application struct {
 	MixArgsAndFlags   bool
 	Author            string
 	Version           string
-	ShellCompletion   bool // if set to true generate command is added that will generate bash or zsh completing shell
-	Terminator        Terminator
+	ShellCompletion   bool // if set to true generate command is added that will generate bash, zsh, fish or PowerShell completion script
+	HandleSignals     bool // if set to true context passed to actions is cancelled on SIGINT or SIGTERM
+	// if set to true commands and long flags can be shortened to any prefix that matches only one of them, i.e. "dep --reg" for "deploy --region"
+	AllowPrefixMatching bool
+	// maximum number of edits between unknown command or flag and known one to suggest it in error ("did you mean"). 0 disables suggestions
+	SuggestionDistance int
+	// if set to true flag value @path is replaced with content of file at path, @@ starts literal value beginning with @
+	ExpandAtFiles bool
+	// if set to true validation does not stop on first error, all validation errors are returned in ValidationErrors
+	ReportAllErrors bool
+	// if set to true missing required flags and arguments are asked for when standard input is a terminal
+	PromptForMissing bool
+	// asks for missing values if PromptForMissing is set, terminal is used if not set
+	Prompter   IPrompter
+	Terminator Terminator
 	// this handler is called after command oline is parced but vefore any validation or prcessing.
 	// it is useful if you have such global flags as log level, output format , etc that you want to confgure BEFOER caling custom (or any) validators
-	GlobalFlagsHandler    GlobalFlagsHandler
+	GlobalFlagsHandler GlobalFlagsHandler
+	// configuration files (yaml, json or toml) used to set flags not set on command line or environment.
+	// Files are applied in order, values from later files override earlier ones. Files that do not exist are ignored.
+	// Keys are scoped by command path, i.e. deploy.region sets --region for "app deploy", region sets it for every command
+	ConfigFiles           []string
+	config                map[string]configEntry
 	errorWriter           io.Writer // Destination for errors.
 	usageWriter           io.Writer // Destination for usage
 	context               *context
+	runContext            gocontext.Context
 	stopActionPropagation bool
-	bashCompletionFlag    IFlag
+	templateManager       *TemplateManager
 	Path                  string
 }
 
@@ -56,23 +78,24 @@ func New() *Application {
 			Name:  filepath.Base(os.Args[0]),
 			Usage: "",
 		},
-		MixArgsAndFlags: true, // default
-		usageWriter:     os.Stdout,
-		errorWriter:     os.Stderr,
-		Terminator:      os.Exit,
-		context:         &context{},
+		MixArgsAndFlags:    true, // default
+		SuggestionDistance: 2,    // default
+		usageWriter:        os.Stdout,
+		errorWriter:        os.Stderr,
+		Terminator:         os.Exit,
+		context:            &context{},
+		templateManager:    newTemplateManager(),
 	}
-	initTemplateManager()
 
 	return app
 }
 
 func (a *Application) GetTemplateManager() *TemplateManager {
-	return templateManager
+	return a.templateManager
 }
 
 func (a Application) SetLanguage(tag language.Tag) {
-	templateManager.localizer.SetLanguage(tag)
+	a.templateManager.localizer.SetLanguage(tag)
 }
 
 func (c *Application) AddArgs(args []IArg) {
@@ -144,36 +167,70 @@ func (a *Application) Stop() {
 	a.stopActionPropagation = true
 }
 
+// Context returns context of current run. It can be used by actions that do not accept context
+func (a *Application) Context() gocontext.Context {
+	if a.runContext == nil {
+		return gocontext.Background()
+	}
+	return a.runContext
+}
+
 // Run :
 //   - parses command-line arguments,
 //   - populates all flags and argumants,
 //   - validates arguments, flags and commands in that order
 //   - executes appropriate command
 func (a *Application) Run(args []string) (err error) {
+	return a.RunContext(gocontext.Background(), args)
+}
+
+// RunContext is the same as Run but passes ctx to actions.
+// If HandleSignals is set, ctx is cancelled on SIGINT or SIGTERM
+func (a *Application) RunContext(ctx gocontext.Context, args []string) (err error) {
 
 	if err := a.init(); err != nil {
 		return err
 	}
+	// constraints are part of definition of commands, misspelled names are reported before anything is parsed
+	if err := a.Command.checkConstraintNames(); err != nil {
+		a.printError(err)
+		return err
+	}
+
+	if a.HandleSignals {
+		var stop gocontext.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+	a.runContext = ctx
+	defer func() { a.runContext = nil }()
 
 	a.context.mixArgsAndFlags = a.MixArgsAndFlags
 
-	err = a.context.parse(a, args[1:])
-	// first check if we doing shell completion and only report parse errors if not
-	if a.checkCompletion(args) {
+	// completion scripts ask for candidates with hidden command, nothing is run then
+	if a.ShellCompletion && len(args) > 1 && args[1] == completeCommand {
+		a.complete(args[2:])
 		return nil
 	}
+
+	if err = a.loadConfigFiles(); err != nil {
+		a.printError(err)
+		return &UsageError{Err: err}
+	}
+
+	err = a.context.parse(a, args[1:])
 	if err != nil {
 		a.printUsage(err)
-		return err
+		return &UsageError{Err: err}
 	}
 
 	if a.GlobalFlagsHandler != nil {
 		a.GlobalFlagsHandler()
 	}
 
-	// if help flag was set app will exit with succsess
+	// if help flag was set usage is printed and ErrHelpRequested is returned
 	if a.checkHelpRequested() {
-		return nil
+		return ErrHelpRequested
 	}
 
 	if a.checkVersionRequested() {
@@ -183,27 +240,26 @@ func (a *Application) Run(args []string) (err error) {
 	err = a.context.validate(a)
 	if err != nil {
 		a.printUsage(err)
-		return err
+		return &ValidationError{Err: err}
 	}
 
 	// execute command actions
-	err = a.context.execute(a)
-	if err != nil {
+	err = a.context.execute(ctx, a)
+	// input and output files are closed even if action failed
+	if close_err := a.context.closeFiles(); close_err != nil && err == nil {
+		err = close_err
+	}
+	if err != nil && !errors.Is(err, ErrHelpRequested) {
 		a.printError(err)
-		return err
 	}
 
 	return err
 }
 
-func (a *Application) checkCompletion(args []string) bool {
-	if a.bashCompletionFlag != nil && a.bashCompletionFlag.GetValue().(bool) {
-		completions := a.context.resolveCompletion(a, args)
-
-		fmt.Printf("%s", strings.Join(completions, "\n"))
-		return true
-	}
-	return false
+// RunAndExit runs application and terminates it with exit status matching returned error, see ExitCode
+func (a *Application) RunAndExit(args []string) {
+	err := a.Run(args)
+	a.Terminate(ExitCode(err))
 }
 
 func (a *Application) checkHelpRequested() bool {
@@ -230,15 +286,45 @@ func (a *Application) checkVersionRequested() bool {
 
 }
 
+// prompter for missing values, nil if prompting is disabled or not possible
+func (a *Application) getPrompter() IPrompter {
+	if !a.PromptForMissing {
+		return nil
+	}
+	if a.Prompter != nil {
+		return a.Prompter
+	}
+	if isTerminal(os.Stdin) {
+		return newTerminalPrompter(os.Stdin, a.errorWriter)
+	}
+	return nil
+}
+
 func (a *Application) printError(err error) {
 
-	if int_err, ok := err.(*i18n.Error); ok {
-		templateManager.FormatTemplate(a.errorWriter, int_err.GetKey(), int_err.GetData())
-		fmt.Fprintln(a.errorWriter)
-	} else {
-		fmt.Fprintln(a.errorWriter, templateManager.GetLocalizedString("Error", err))
+	var all_errs ValidationErrors
+	if errors.As(err, &all_errs) {
+		buf := bytes.NewBuffer(nil)
+		a.templateManager.FormatTemplate(buf, "ValidationErrorsTemplate", all_errs)
+		fmt.Fprintln(a.errorWriter, strings.TrimSpace(buf.String()))
+		for _, e := range all_errs {
+			fmt.Fprintln(a.errorWriter, a.templateManager.GetLocalizedString("ValidationErrorsItem", strings.TrimSpace(a.formatError(e))))
+		}
+		return
+	}
+	fmt.Fprintln(a.errorWriter, a.formatError(err))
+}
+
+func (a *Application) formatError(err error) string {
+	var int_err *i18n.Error
+	if errors.As(err, &int_err) {
+		buf := bytes.NewBuffer(nil)
+		a.templateManager.FormatTemplate(buf, int_err.GetKey(), int_err.GetData())
+		return buf.String()
 	}
+	return a.templateManager.GetLocalizedString("Error", err)
 }
+
 func (a *Application) printUsage(err error) {
 	if err != nil && err.Error() != "" {
 		a.printError(err)
@@ -246,9 +332,7 @@ func (a *Application) printUsage(err error) {
 
 	if err := a.formatUsage(); err != nil {
 		fmt.Fprintln(a.errorWriter, err.Error())
-		a.Terminate(1)
 	}
-	a.Terminate(0)
 }
 
 func (a *Application) formatUsage() error {
@@ -269,18 +353,19 @@ func (a *Application) formatUsage() error {
 		UseOptionsCommand: a.UseOptionsCommand,
 	}
 
-	return templateManager.FormatTemplate(a.usageWriter, "AppUsageTemplate", templateCtx, WithOutput(TemplateTerminal))
+	return a.templateManager.FormatTemplate(a.usageWriter, "AppUsageTemplate", templateCtx, WithOutput(TemplateTerminal))
 }
 
 func (a *Application) GetHelpFlag() IFlag {
 
 	if a.helpFlag == nil {
 		// add help flag - it is always present
-		help_short, _ := utf8.DecodeRuneInString(templateManager.GetLocalizedString("HelpFlagShort"))
+		help_short, _ := utf8.DecodeRuneInString(a.templateManager.GetLocalizedString("HelpFlagShort"))
 		a.helpFlag = &Flag[Bool]{
-			Name:  templateManager.GetLocalizedString("HelpCommandAndFlagName"),
-			Short: help_short,
-			Usage: templateManager.GetLocalizedString("HelpFlagUsageTemplate"),
+			Name:            a.templateManager.GetLocalizedString("HelpCommandAndFlagName"),
+			Short:           help_short,
+			Usage:           a.templateManager.GetLocalizedString("HelpFlagUsageTemplate"),
+			DisableNegation: true,
 		}
 		a.AddFlag(a.helpFlag)
 	}
@@ -289,12 +374,13 @@ func (a *Application) GetHelpFlag() IFlag {
 func (a *Application) GetVersionFlag() IFlag {
 	// add version flag is version value is set
 	if a.Version != "" && a.versionFlag == nil {
-		version_short, _ := utf8.DecodeRuneInString(templateManager.GetLocalizedString("VersionFlagShort"))
+		version_short, _ := utf8.DecodeRuneInString(a.templateManager.GetLocalizedString("VersionFlagShort"))
 
 		a.versionFlag = &Flag[Bool]{
-			Name:  templateManager.GetLocalizedString("VersionFlagName"),
-			Short: version_short,
-			Usage: templateManager.GetLocalizedString("VersionFlagUsageTemplate"),
+			Name:            a.templateManager.GetLocalizedString("VersionFlagName"),
+			Short:           version_short,
+			Usage:           a.templateManager.GetLocalizedString("VersionFlagUsageTemplate"),
+			DisableNegation: true,
 		}
 		a.AddFlag(a.versionFlag)
 	}
@@ -302,8 +388,8 @@ func (a *Application) GetVersionFlag() IFlag {
 }
 
 func (a *Application) GenerateBashCompletion(writer io.Writer, kind string) error {
-	template := cases.Title(templateManager.localizer.GetLanguage()).String(kind) + "CompletionTemplate"
-	return templateManager.FormatTemplate(writer, template, a, WithOutput(TemplateText))
+	template := cases.Title(a.templateManager.localizer.GetLanguage()).String(kind) + "CompletionTemplate"
+	return a.templateManager.FormatTemplate(writer, template, a, WithOutput(TemplateText))
 }
 
 func (a *Application) init() error {
@@ -314,75 +400,77 @@ func (a *Application) init() error {
 	// make sure we create help flag
 	a.GetHelpFlag()
 
+	// secret flags can be read from files
+	a.addSecretFileFlags(&a.Command)
+
 	if a.ShellCompletion {
+		shell_arg := a.templateManager.GetLocalizedString("ShellCompletionArgName")
+		output_flag := a.templateManager.GetLocalizedString("ShellCompletionOutputFlagName")
+		install_flag := a.templateManager.GetLocalizedString("ShellCompletionInstallFlagName")
 		a.AddCommand(Command{
-			Name:        templateManager.GetLocalizedString("ShellCompletionCommand"),
-			Description: templateManager.GetLocalizedString("ShellCompletionCommandDesc"),
+			Name:        a.templateManager.GetLocalizedString("ShellCompletionCommand"),
+			Description: a.templateManager.GetLocalizedString("ShellCompletionCommandDesc"),
 			Args: []IArg{
 				&Arg[OneOf]{
-					Name:     templateManager.GetLocalizedString("ShellCompletionArgName"),
-					Usage:    templateManager.GetLocalizedString("ShellCompetionArgUsage"),
-					Hints:    []string{"bash", "zsh"},
+					Name:     shell_arg,
+					Usage:    a.templateManager.GetLocalizedString("ShellCompetionArgUsage"),
+					Hints:    completionShells,
 					Default:  "bash",
 					Required: false,
 				},
 			},
+			Flags: []IFlag{
+				&Flag[OutputFile]{
+					Name:  output_flag,
+					Usage: a.templateManager.GetLocalizedString("ShellCompletionOutputFlagUsage"),
+				},
+				&Flag[Bool]{
+					Name:  install_flag,
+					Usage: a.templateManager.GetLocalizedString("ShellCompletionInstallFlagUsage"),
+				},
+			},
+			Constraints: Constraints{
+				ConflictsWith: map[string][]string{output_flag: {install_flag}},
+			},
 			Action: func(a *Application, c *Command, i interface{}) (interface{}, error) {
 
 				a.Path = os.Args[0]
 
-				shell, _ := a.GetArgumentValue("shell")
-
-				f, err := os.Create("completion." + shell.(string))
-				if err != nil {
-					fmt.Println(err.Error())
-				}
-				defer f.Close()
-				w := bufio.NewWriter(f)
-
-				err = a.GenerateBashCompletion(w, shell.(string))
-				if err != nil {
-					fmt.Println(err.Error())
-					return nil, err
+				shell, _ := a.GetArgumentValue(shell_arg)
+				install, _ := a.GetFlagValue(install_flag)
+				// script goes to usage writer unless output file is given
+				var output io.Writer = a.usageWriter
+				if out, _ := a.GetFlagValue(output_flag); out != nil {
+					output = out.(io.Writer)
 				}
-				w.Flush()
-				return nil, nil
+				return nil, a.writeCompletion(output, shell.(string), install.(bool))
 			},
 		})
-
-		// add bash completion flag
-		a.bashCompletionFlag = &Flag[Bool]{
-			Name:     "bash-completions", // not localizable - internal
-			Usage:    templateManager.GetLocalizedString("ShellCompletionFlagUsageTemplate"),
-			Hidden:   true,
-			internal: true,
-		}
-		a.AddFlag(a.bashCompletionFlag)
 	}
 
 	// If we have subcommands, add a help command at the top-level.
 	if a.ShowHelpCommand {
-		command_arg_name := templateManager.GetLocalizedString("CommandArgName")
+		command_arg_name := a.templateManager.GetLocalizedString("CommandArgName")
 		help_cmd := &Command{
-			Name:  templateManager.GetLocalizedString("HelpCommandAndFlagName"),
-			Usage: templateManager.GetLocalizedString("HelpCommandUsage"),
+			Name:  a.templateManager.GetLocalizedString("HelpCommandAndFlagName"),
+			Usage: a.templateManager.GetLocalizedString("HelpCommandUsage"),
 			Args: []IArg{
 				&Arg[[]String]{
 					Name:  command_arg_name,
-					Usage: templateManager.GetLocalizedString("HelpCommandArgUsage"),
+					Usage: a.templateManager.GetLocalizedString("HelpCommandArgUsage"),
 				},
 			},
 			Action: func(app *Application, c *Command, in_data interface{}) (interface{}, error) {
 				cmd_arg, err := a.GetArgument(command_arg_name)
-				command := cmd_arg.GetValue().([]string)
-				if err != nil {
-					a.printUsage(nil)
+				if err == nil {
+					// help for unknown command is a usage error, not a help request
+					if err = a.context.parse(a, cmd_arg.GetValue().([]string)); err != nil {
+						return nil, &UsageError{Err: err}
+					}
 				}
-				a.context.parse(a, command)
 
 				a.printUsage(nil)
-				a.Terminate(0)
-				return nil, nil
+				return nil, ErrHelpRequested
 			},
 		}
 		// make help first command
@@ -392,42 +480,42 @@ func (a *Application) init() error {
 
 	// add command to generate documentation
 	a.AddCommand(Command{
-		Name:        templateManager.GetLocalizedString("DocGenerationCommand"),
-		Description: templateManager.GetLocalizedString("DocGenerationCommandDesc"),
+		Name:        a.templateManager.GetLocalizedString("DocGenerationCommand"),
+		Description: a.templateManager.GetLocalizedString("DocGenerationCommandDesc"),
 		Usage:       "",
 		Args: []IArg{
 			&Arg[OneOf]{
-				Name:     templateManager.GetLocalizedString("DocGenerationFormatArgName"),
-				Usage:    templateManager.GetLocalizedString("DocGenerationFormatArgUsage"),
+				Name:     a.templateManager.GetLocalizedString("DocGenerationFormatArgName"),
+				Usage:    a.templateManager.GetLocalizedString("DocGenerationFormatArgUsage"),
 				Hints:    []string{string(TemplateHTML), string(TemplateMarkdown), string(TemplateManpage)},
 				Required: false,
 				Default:  string(TemplateMarkdown),
 			}},
 		Flags: []IFlag{
 			&Flag[String]{
-				Name:     templateManager.GetLocalizedString("DocGenerationCssFlagName"),
-				Usage:    templateManager.GetLocalizedString("DocGenerationCssFlagUsage"),
+				Name:     a.templateManager.GetLocalizedString("DocGenerationCssFlagName"),
+				Usage:    a.templateManager.GetLocalizedString("DocGenerationCssFlagUsage"),
 				Default:  "",
 				Required: false,
 			},
 			&Flag[String]{
-				Name:     templateManager.GetLocalizedString("DocGenerationIconFlagName"),
-				Usage:    templateManager.GetLocalizedString("DocGenerationIconFlagUsage"),
+				Name:     a.templateManager.GetLocalizedString("DocGenerationIconFlagName"),
+				Usage:    a.templateManager.GetLocalizedString("DocGenerationIconFlagUsage"),
 				Default:  "",
 				Required: false,
 			},
 			&Flag[Bool]{
-				Name:     templateManager.GetLocalizedString("DocGenerationTocFlagName"),
-				Usage:    templateManager.GetLocalizedString("DocGenerationTocFlagUsage"),
+				Name:     a.templateManager.GetLocalizedString("DocGenerationTocFlagName"),
+				Usage:    a.templateManager.GetLocalizedString("DocGenerationTocFlagUsage"),
 				Required: false,
 				Default:  "false",
 			},
 		},
 		Action: func(a *Application, c *Command, i interface{}) (interface{}, error) {
-			format, _ := a.GetArgumentValue(templateManager.GetLocalizedString("DocGenerationFormatArgName"))
-			css, _ := a.GetFlagValue(templateManager.GetLocalizedString("DocGenerationCssFlagName"))
-			icon, _ := a.GetFlagValue(templateManager.GetLocalizedString("DocGenerationIconFlagName"))
-			toc, _ := a.GetFlagValue(templateManager.GetLocalizedString("DocGenerationTocFlagName"))
+			format, _ := a.GetArgumentValue(a.templateManager.GetLocalizedString("DocGenerationFormatArgName"))
+			css, _ := a.GetFlagValue(a.templateManager.GetLocalizedString("DocGenerationCssFlagName"))
+			icon, _ := a.GetFlagValue(a.templateManager.GetLocalizedString("DocGenerationIconFlagName"))
+			toc, _ := a.GetFlagValue(a.templateManager.GetLocalizedString("DocGenerationTocFlagName"))
 
 			// documentation is generated recurcively starting with app
 			buf := bytes.NewBuffer(nil)
@@ -435,7 +523,7 @@ func (a *Application) init() error {
 			if err := a.generateDocumentation(buf, a.Command, make([]string, 0), 0); err != nil {
 				return nil, err
 			}
-			return nil, templateManager.generateTemplateOutput(a.usageWriter, buf,
+			return nil, a.templateManager.generateTemplateOutput(a.usageWriter, buf,
 				WithTitle(a.Name),
 				WithOutput(OutputFormat(format.(string))),
 				WithCSS(css.(string)),
@@ -466,9 +554,9 @@ func (a *Application) generateDocumentation(buf *bytes.Buffer, cmd Command, args
 		Level:          level,
 		DocGeneration:  true,
 	}
-	templateManager.currentLevel = level
+	a.templateManager.currentLevel = level
 
-	if err := templateManager.doFormatTemplate(buf, "AppUsageTemplate", templateCtx); err != nil {
+	if err := a.templateManager.doFormatTemplate(buf, "AppUsageTemplate", templateCtx); err != nil {
 		return err
 	}
 
@@ -1,9 +1,16 @@
 package gocli
 
 import (
+	"bytes"
+	gocontext "context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 
 	"testing"
 
@@ -25,11 +32,15 @@ func TestApplication_Run(t *testing.T) {
 	var action_result string
 
 	tests := []struct {
-		name    string
-		args    []string
-		setup   func() *Application
-		wantErr bool
-		check   func(a *Application) error
+		name      string
+		args      []string
+		setup     func() *Application
+		env       map[string]string // environment variables set for the test
+		config    map[string]string // configuration files written to temporary directory
+		configs   []string          // configuration files of application in temporary directory, in order
+		wantErr   bool
+		wantErrIs error
+		check     func(a *Application) error
 	}{
 		{
 			name: "no cmd",
@@ -67,8 +78,9 @@ func TestApplication_Run(t *testing.T) {
 
 				return app
 			},
-			args:    []string{"test", "-h"},
-			wantErr: false,
+			args:      []string{"test", "-h"},
+			wantErr:   true, // usage is printed and ErrHelpRequested returned
+			wantErrIs: ErrHelpRequested,
 		},
 		{
 			name: "long help flag",
@@ -87,8 +99,9 @@ func TestApplication_Run(t *testing.T) {
 
 				return app
 			},
-			args:    []string{"test", "--help"},
-			wantErr: false,
+			args:      []string{"test", "--help"},
+			wantErr:   true, // usage is printed and ErrHelpRequested returned
+			wantErrIs: ErrHelpRequested,
 		},
 		{
 			name: "long version flag",
@@ -159,8 +172,9 @@ func TestApplication_Run(t *testing.T) {
 				app.SetLanguage(ru_tag)
 				return app
 			},
-			args:    []string{"test", "--Помощь"},
-			wantErr: false,
+			args:      []string{"test", "--Помощь"},
+			wantErr:   true, // usage is printed and ErrHelpRequested returned
+			wantErrIs: ErrHelpRequested,
 		},
 		{
 			name: "flag sorting",
@@ -192,8 +206,9 @@ func TestApplication_Run(t *testing.T) {
 				app.Terminator = NilTerminator
 				return app
 			},
-			args:    []string{"test", "command1", "-h"},
-			wantErr: false,
+			args:      []string{"test", "command1", "-h"},
+			wantErr:   true, // usage is printed and ErrHelpRequested returned
+			wantErrIs: ErrHelpRequested,
 		},
 
 		{
@@ -1355,19 +1370,186 @@ func TestApplication_Run(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			// flags and arguments not set on command line are taken from environment before defaults
+			// Should succeed
+			name: "environment variables",
+			env:  map[string]string{"GOCLI_TEST_TOKEN": "env-token", "GOCLI_TEST_REGION": "env-region", "GOCLI_TEST_NAME": "env-name"},
+			setup: func() *Application {
+				app := New()
+				app.AddCommand(Command{
+					Name: "command1",
+					Flags: []IFlag{
+						&Flag[String]{
+							Name:    "token",
+							EnvVars: []string{"GOCLI_TEST_MISSING", "GOCLI_TEST_TOKEN"},
+							Default: "default-token",
+						},
+						&Flag[String]{
+							Name:    "region",
+							EnvVars: []string{"GOCLI_TEST_REGION"},
+						},
+						&Flag[String]{
+							Name:    "output",
+							EnvVars: []string{"GOCLI_TEST_MISSING"},
+							Default: "table",
+						},
+					},
+					Args: []IArg{
+						&Arg[String]{
+							Name:     "name",
+							EnvVars:  []string{"GOCLI_TEST_NAME"},
+							Required: true,
+						},
+					},
+				})
+				app.Terminator = NilTerminator
+				return app
+			},
+			args:    []string{"test", "command1", "--region", "cli-region"},
+			wantErr: false,
+			check: func(a *Application) error {
+				expected := map[string]struct {
+					value  string
+					source ValueSource
+				}{
+					"token":  {"env-token", SourceEnvironment},
+					"region": {"cli-region", SourceCommandLine},
+					"output": {"table", SourceDefault},
+				}
+				for name, e := range expected {
+					f, err := a.GetFlag(name)
+					if err != nil {
+						return err
+					}
+					if f.GetValue().(string) != e.value || f.GetSource() != e.source {
+						return errors.New("unexpected value or source for flag " + name)
+					}
+				}
+				arg, err := a.GetArgument("name")
+				if err != nil {
+					return err
+				}
+				if arg.GetValue().(string) != "env-name" || arg.GetSource() != SourceEnvironment {
+					return errors.New("unexpected value or source for argument name")
+				}
+				return nil
+			},
+		},
+		{
+			// flags not set on command line or environment are taken from config files scoped by command path
+			// Should succeed
+			name: "config files",
+			config: map[string]string{
+				"config.yaml": "output: json\nlabels: [a, b]\ndeploy:\n  region: us-east\n  replicas: 3\n",
+				"config.json": `{"deploy": {"replicas": 5}}`,
+			},
+			configs: []string{"config.yaml", "config.json", "missing.toml"},
+			setup: func() *Application {
+				app := New()
+				app.AddFlag(&Flag[String]{
+					Name:    "output",
+					Default: "table",
+				})
+				app.AddCommand(Command{
+					Name: "deploy",
+					Flags: []IFlag{
+						&Flag[String]{
+							Name: "region",
+						},
+						&Flag[Int]{
+							Name: "replicas",
+						},
+						&Flag[[]String]{
+							Name: "labels",
+						},
+						&Flag[String]{
+							Name:    "zone",
+							Default: "a",
+						},
+					},
+				})
+				app.Terminator = NilTerminator
+				return app
+			},
+			args:    []string{"test", "deploy", "--region", "eu-west"},
+			wantErr: false,
+			check: func(a *Application) error {
+				expected := map[string]struct {
+					value  interface{}
+					source ValueSource
+				}{
+					"output":   {"json", SourceConfigFile},
+					"region":   {"eu-west", SourceCommandLine},
+					"replicas": {5, SourceConfigFile},
+					"labels":   {[]string{"a", "b"}, SourceConfigFile},
+					"zone":     {"a", SourceDefault},
+				}
+				for name, e := range expected {
+					f, err := a.GetFlag(name)
+					if err != nil {
+						return err
+					}
+					if !reflect.DeepEqual(f.GetValue(), e.value) || f.GetSource() != e.source {
+						return errors.New("unexpected value or source for flag " + name)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// invalid value in config file is reported with file and key
+			// Should fail
+			name:    "config files - invalid value",
+			config:  map[string]string{"invalid.toml": "[deploy]\nreplicas = \"many\"\n"},
+			configs: []string{"invalid.toml"},
+			setup: func() *Application {
+				app := New()
+				app.AddCommand(Command{
+					Name: "deploy",
+					Flags: []IFlag{
+						&Flag[Int]{
+							Name: "replicas",
+						},
+					},
+				})
+				app.Terminator = NilTerminator
+				return app
+			},
+			args:    []string{"test", "deploy"},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
 			t.Logf("Running test %s", tt.name)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
 			a := tt.setup()
+			dir := t.TempDir()
+			for name, content := range tt.config {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0666); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, name := range tt.configs {
+				a.ConfigFiles = append(a.ConfigFiles, filepath.Join(dir, name))
+			}
 			if len(tt.args) > 0 {
-				if err := a.Run(tt.args); (err != nil) != tt.wantErr {
+				err := a.Run(tt.args)
+				if (err != nil) != tt.wantErr {
 					t.Errorf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
 				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("Application.Run() error = %v, want %v", err, tt.wantErrIs)
+				}
 			}
 			if tt.check != nil {
-				tt.check(a)
+				if err := tt.check(a); err != nil {
+					t.Errorf("Application.Run() check failed: %v", err)
+				}
 			}
 		})
 	}
@@ -1377,3 +1559,1168 @@ func TestApplication_Run(t *testing.T) {
 		os.Remove(f)
 	}
 }
+
+func TestApplication_RunConcurrent(t *testing.T) {
+
+	ru_tag := language.MustParse("ru")
+	newApp := func(russian bool) (*Application, *bytes.Buffer) {
+		out := bytes.NewBuffer(nil)
+		app := New()
+		app.AddCommand(Command{
+			Name:        "command1",
+			Description: `{{.FullCommand}} first command`,
+			Flags: []IFlag{
+				&Flag[String]{
+					Name:  "name",
+					Usage: "name to use",
+				},
+			},
+		})
+		app.SetWriter(out)
+		app.SetErrorWriter(out)
+		app.Terminator = NilTerminator
+		if russian {
+			app.GetTemplateManager().AddTranslation(ru_tag, i18n.Entries{
+				"HelpCommandAndFlagName": "Помощь",
+				"Options":                "Опции",
+			})
+			app.SetLanguage(ru_tag)
+		} else {
+			app.GetTemplateManager().UpdateTranslation(language.MustParse("en_us"), "Options", "Settings")
+		}
+		return app, out
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		russian := i%2 == 0
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app, out := newApp(russian)
+			help := "--help"
+			expected := "Settings"
+			if russian {
+				help = "--Помощь"
+				expected = "Опции"
+			}
+			app.Run([]string{"test", "command1", help})
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("expected %q in usage, got %s", expected, out.String())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestApplication_RunContext(t *testing.T) {
+
+	type ctxKey string
+
+	newApp := func(received *[]string) *Application {
+		app := New()
+		app.Action = func(a *Application, c *Command, i interface{}) (interface{}, error) {
+			// plain action still runs and can see run context
+			*received = append(*received, "app:"+a.Context().Value(ctxKey("key")).(string))
+			return nil, nil
+		}
+		app.AddCommand(Command{
+			Name: "command1",
+			ContextAction: func(ctx gocontext.Context, a *Application, c *Command, i interface{}) (interface{}, error) {
+				*received = append(*received, "command1:"+ctx.Value(ctxKey("key")).(string))
+				return nil, ctx.Err()
+			},
+		})
+		app.Terminator = NilTerminator
+		app.SetErrorWriter(bytes.NewBuffer(nil))
+		return app
+	}
+
+	received := make([]string, 0)
+	ctx := gocontext.WithValue(gocontext.Background(), ctxKey("key"), "value")
+	if err := newApp(&received).RunContext(ctx, []string{"test", "command1"}); err != nil {
+		t.Errorf("Application.RunContext() error = %v", err)
+	}
+	if !reflect.DeepEqual(received, []string{"command1:value", "app:value"}) {
+		t.Errorf("Application.RunContext() actions received %v", received)
+	}
+
+	// cancelled context is reported by action and stops propagation
+	received = make([]string, 0)
+	ctx, cancel := gocontext.WithCancel(ctx)
+	cancel()
+	if err := newApp(&received).RunContext(ctx, []string{"test", "command1"}); !errors.Is(err, gocontext.Canceled) {
+		t.Errorf("Application.RunContext() error = %v, want %v", err, gocontext.Canceled)
+	}
+	if len(received) != 0 {
+		t.Errorf("Application.RunContext() actions called after cancel: %v", received)
+	}
+
+	// ActionWrapper of ICommand keeps its signature and runs context action outside of run with background context
+	var cmd ICommand = &Command{
+		ContextAction: func(ctx gocontext.Context, a *Application, c *Command, i interface{}) (interface{}, error) {
+			return ctx.Err() == nil, nil
+		},
+	}
+	if data, err := cmd.ActionWrapper(New(), nil); data != true || err != nil {
+		t.Errorf("Command.ActionWrapper() = %v, %v", data, err)
+	}
+}
+
+func TestApplication_RunAndExit(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+	}{
+		{name: "success", args: []string{"test", "command1", "--count", "1"}, status: ExitOK},
+		{name: "help", args: []string{"test", "--help"}, status: ExitOK},
+		{name: "help command", args: []string{"test", "help", "command1"}, status: ExitOK},
+		{name: "help command for unknown command", args: []string{"test", "help", "command2"}, status: ExitUsage},
+		{name: "help with unknown flag", args: []string{"test", "command1", "--help", "--unknown"}, status: ExitUsage},
+		{name: "unknown flag", args: []string{"test", "command1", "--unknown"}, status: ExitUsage},
+		{name: "missing required flag", args: []string{"test", "command1"}, status: ExitUsage},
+		{name: "action error", args: []string{"test", "command1", "--count", "2"}, status: ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.ShowHelpCommand = true
+			app.AddCommand(Command{
+				Name: "command1",
+				Flags: []IFlag{
+					&Flag[Int]{
+						Name:     "count",
+						Required: true,
+					},
+				},
+				Action: func(a *Application, c *Command, i interface{}) (interface{}, error) {
+					count, _ := a.GetFlagValue("count")
+					if count.(int) > 1 {
+						return nil, errors.New("count is too big")
+					}
+					return nil, nil
+				},
+			})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			status := -1
+			app.Terminator = func(s int) { status = s }
+			app.RunAndExit(tt.args)
+			if status != tt.status {
+				t.Errorf("Application.RunAndExit() status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+}
+
+func TestApplication_Suggestions(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		args     []string
+		distance int
+		want     string
+	}{
+		{name: "command", args: []string{"test", "delpoy"}, distance: 2, want: "did you mean deploy?"},
+		{name: "command alias", args: []string{"test", "dap"}, distance: 1, want: "did you mean dep?"},
+		{name: "long flag", args: []string{"test", "deploy", "--regoin", "us"}, distance: 2, want: "did you mean --region?"},
+		{name: "several", args: []string{"test", "deploy", "--zone"}, distance: 2, want: "did you mean --done or --zones?"},
+		{name: "disabled", args: []string{"test", "deploy", "--regoin", "us"}, distance: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.SuggestionDistance = tt.distance
+			app.AddCommand(Command{
+				Name:  "deploy",
+				Alias: []string{"dep"},
+				Flags: []IFlag{
+					&Flag[String]{Name: "region"},
+					&Flag[Bool]{Name: "done"},
+					&Flag[[]String]{Name: "zones"},
+				},
+			})
+			app.AddCommand(Command{Name: "status"})
+			errors_out := bytes.NewBuffer(nil)
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(errors_out)
+			if err := app.Run(tt.args); err == nil {
+				t.Fatalf("Application.Run() expected error")
+			}
+			got := errors_out.String()
+			if tt.want == "" && strings.Contains(got, "did you mean") {
+				t.Errorf("unexpected suggestion in %q", got)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("expected %q in %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplication_PrefixMatching(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		args    []string
+		allow   bool
+		wantErr string
+		command string
+		region  string
+		token   string
+	}{
+		{name: "command and flag", args: []string{"test", "dep", "--reg", "us"}, allow: true, command: "deploy", region: "us"},
+		{name: "exact match wins", args: []string{"test", "de", "--reg=us"}, allow: true, command: "de", region: "us"},
+		{name: "alias prefix", args: []string{"test", "sta"}, allow: true, command: "show"},
+		{name: "ambiguous command", args: []string{"test", "d"}, allow: true, wantErr: "command d is ambiguous, could be de, deploy"},
+		{name: "ambiguous flag", args: []string{"test", "deploy", "--re", "us"}, allow: true, wantErr: "flag --re is ambiguous, could be --region, --replicas"},
+		{name: "disabled", args: []string{"test", "dep", "--reg", "us"}, allow: false, wantErr: "expected command but got dep"},
+		{name: "secret flag over its file flag", args: []string{"test", "deploy", "--tok", "abc"}, allow: true, command: "deploy", token: "abc"},
+		{name: "file flag of secret flag", args: []string{"test", "deploy", "--token-f", "missing"}, allow: true, wantErr: "flag token-file: path missing does not exist"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed := ""
+			action := func(a *Application, c *Command, i interface{}) (interface{}, error) {
+				if executed == "" {
+					executed = c.Name
+				}
+				return nil, nil
+			}
+			flags := func() []IFlag {
+				return []IFlag{
+					&Flag[String]{Name: "region"},
+					&Flag[Int]{Name: "replicas"},
+					&Flag[String]{Name: "token", Secret: true},
+					&Flag[String]{Name: "region-override", Hidden: true},
+				}
+			}
+			app := New()
+			app.AllowPrefixMatching = tt.allow
+			app.AddCommand(Command{Name: "deploy", Flags: flags(), Action: action})
+			app.AddCommand(Command{Name: "de", Flags: flags(), Action: action})
+			app.AddCommand(Command{Name: "show", Alias: []string{"status"}, Action: action})
+			errors_out := bytes.NewBuffer(nil)
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(errors_out)
+			err := app.Run(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(errors_out.String(), tt.wantErr) {
+					t.Fatalf("expected error %q, got %v: %q", tt.wantErr, err, errors_out.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Application.Run() error = %v", err)
+			}
+			if executed != tt.command {
+				t.Errorf("executed %q, want %q", executed, tt.command)
+			}
+			if tt.region != "" {
+				if region, _ := app.GetFlagValue("region"); region != tt.region {
+					t.Errorf("region = %v, want %v", region, tt.region)
+				}
+			}
+			if tt.token != "" {
+				if token, _ := app.GetFlagValue("token"); token != tt.token {
+					t.Errorf("token = %v, want %v", token, tt.token)
+				}
+			}
+		})
+	}
+}
+
+func TestApplication_NegatableFlags(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default", args: []string{"test", "run"}, want: true},
+		{name: "negated", args: []string{"test", "run", "--no-color"}, want: false},
+		{name: "explicit false", args: []string{"test", "run", "--color=false"}, want: false},
+		{name: "explicit 0", args: []string{"test", "run", "--color=0"}, want: false},
+		{name: "explicit true", args: []string{"test", "run", "--color=true"}, want: true},
+		{name: "invalid value", args: []string{"test", "run", "--color=maybe"}, wantErr: true},
+		{name: "negated with value", args: []string{"test", "run", "--no-color=true"}, wantErr: true},
+		{name: "negation disabled", args: []string{"test", "run", "--no-force"}, wantErr: true},
+		{name: "no help negation", args: []string{"test", "run", "--no-help"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.AddFlags([]IFlag{
+				&Flag[Bool]{Name: "color", Default: "true"},
+				&Flag[Bool]{Name: "force", DisableNegation: true},
+			})
+			app.AddCommand(Command{Name: "run"})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if color, _ := app.GetFlagValue("color"); color != tt.want {
+					t.Errorf("color = %v, want %v", color, tt.want)
+				}
+			}
+		})
+	}
+
+	// help shows negative form
+	app := New()
+	app.AddFlag(&Flag[Bool]{Name: "color", Usage: "colorize output"})
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "--help"})
+	if !strings.Contains(out.String(), "--[no-]color") || strings.Contains(out.String(), "[no-]help") {
+		t.Errorf("unexpected help output %s", out.String())
+	}
+}
+
+func TestApplication_CounterFlags(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{name: "not set", args: []string{"test", "run"}, want: 0},
+		{name: "single", args: []string{"test", "run", "-v"}, want: 1},
+		{name: "repeated short", args: []string{"test", "run", "-v", "-v"}, want: 2},
+		{name: "combined short", args: []string{"test", "run", "-vvv"}, want: 3},
+		{name: "combined with other flag", args: []string{"test", "run", "-vqv"}, want: 2},
+		{name: "repeated long", args: []string{"test", "run", "--verbose", "--verbose"}, want: 2},
+		{name: "mixed", args: []string{"test", "run", "-vv", "--verbose"}, want: 3},
+		{name: "explicit", args: []string{"test", "run", "--verbose=5"}, want: 5},
+		{name: "invalid value", args: []string{"test", "run", "--verbose=loud"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.AddFlags([]IFlag{
+				&Flag[Counter]{Name: "verbose", Short: 'v'},
+				&Flag[Bool]{Name: "quiet", Short: 'q'},
+			})
+			app.AddCommand(Command{Name: "run"})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if verbose, _ := app.GetFlagValue("verbose"); verbose != tt.want {
+					t.Errorf("verbose = %v, want %v", verbose, tt.want)
+				}
+			}
+		})
+	}
+
+	// help shows flag as repeatable
+	app := New()
+	app.AddFlag(&Flag[Counter]{Name: "verbose", Short: 'v', Usage: "more output"})
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "--help"})
+	if !strings.Contains(out.String(), "(repeatable)") || !strings.Contains(out.String(), "-v...") {
+		t.Errorf("unexpected help output %s", out.String())
+	}
+}
+
+func TestApplication_MapFlags(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		args    []string
+		config  string
+		labels  map[string]string
+		limits  map[string]int
+		wantErr bool
+	}{
+		{name: "not set", args: []string{"test", "run"}, labels: map[string]string{}, limits: map[string]int{}},
+		{
+			name:   "repeated",
+			args:   []string{"test", "run", "--label", "a=1", "--label", "b=x=y"},
+			labels: map[string]string{"a": "1", "b": "x=y"},
+			limits: map[string]int{},
+		},
+		{
+			name:   "comma separated",
+			args:   []string{"test", "run", "--label=a=1,b=2", "--limit", "cpu=2,mem=512"},
+			labels: map[string]string{"a": "1", "b": "2"},
+			limits: map[string]int{"cpu": 2, "mem": 512},
+		},
+		{
+			name:   "later value wins",
+			args:   []string{"test", "run", "--label", "a=1", "--label", "a=2"},
+			labels: map[string]string{"a": "2"},
+			limits: map[string]int{},
+		},
+		{
+			name:   "config file",
+			args:   []string{"test", "run"},
+			config: "limit:\n  cpu: 4\n",
+			labels: map[string]string{},
+			limits: map[string]int{"cpu": 4},
+		},
+		{name: "missing value", args: []string{"test", "run", "--label", "a"}, wantErr: true},
+		{name: "empty key", args: []string{"test", "run", "--label", "=1"}, wantErr: true},
+		{name: "invalid int", args: []string{"test", "run", "--limit", "cpu=many"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			if tt.config != "" {
+				path := filepath.Join(t.TempDir(), "map.yaml")
+				if err := os.WriteFile(path, []byte(tt.config), 0666); err != nil {
+					t.Fatal(err)
+				}
+				app.ConfigFiles = []string{path}
+			}
+			app.AddFlags([]IFlag{
+				&Flag[map[String]String]{Name: "label"},
+				&Flag[map[String]Int]{Name: "limit"},
+			})
+			app.AddCommand(Command{Name: "run"})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if labels, _ := app.GetFlagValue("label"); !reflect.DeepEqual(labels, tt.labels) {
+					t.Errorf("label = %v, want %v", labels, tt.labels)
+				}
+				if limits, _ := app.GetFlagValue("limit"); !reflect.DeepEqual(limits, tt.limits) {
+					t.Errorf("limit = %v, want %v", limits, tt.limits)
+				}
+			}
+		})
+	}
+
+	// help shows key=value placeholder
+	app := New()
+	app.AddFlag(&Flag[map[String]String]{Name: "label", Usage: "labels"})
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "--help"})
+	if !strings.Contains(out.String(), "--label[=]<KEY=VALUE>...") {
+		t.Errorf("unexpected help output %s", out.String())
+	}
+}
+
+func TestApplication_Range(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantErr       bool
+		wantValidated bool
+	}{
+		{name: "within range", args: []string{"test", "run", "--replicas", "3", "--ratio", "50%", "--size", "1MiB"}, wantValidated: true},
+		{name: "bounds", args: []string{"test", "run", "--replicas", "1", "--replicas", "10"}},
+		{name: "below min", args: []string{"test", "run", "--replicas", "0"}, wantErr: true},
+		{name: "above max", args: []string{"test", "run", "--replicas", "11"}, wantErr: true},
+		{name: "above max percent", args: []string{"test", "run", "--ratio", "101%"}, wantErr: true},
+		{name: "max with units", args: []string{"test", "run", "--size", "2GiB"}, wantErr: true},
+		{name: "argument", args: []string{"test", "run", "0.5"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validated := false
+			app := New()
+			app.AddFlags([]IFlag{
+				&Flag[[]Int]{Name: "replicas", Min: "1", Max: "10"},
+				&Flag[Percent]{Name: "ratio", Max: "100"},
+				&Flag[Bytes]{Name: "size", Max: "1GiB", Validator: func(a *Application, f IFlag) error {
+					validated = f.IsSetByUser()
+					return nil
+				}},
+			})
+			app.AddCommand(Command{
+				Name: "run",
+				Args: []IArg{&Arg[Float]{Name: "scale", Min: "1"}},
+			})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if validated != tt.wantValidated {
+				t.Errorf("validator called = %v, want %v", validated, tt.wantValidated)
+			}
+			if err != nil && !errors.As(err, new(*ValidationError)) {
+				t.Errorf("Application.Run() error = %v, want ValidationError", err)
+			}
+		})
+	}
+
+	// help shows range
+	app := New()
+	app.AddFlags([]IFlag{
+		&Flag[Int]{Name: "replicas", Usage: "number of replicas", Min: "1", Max: "10"},
+		&Flag[Float]{Name: "scale", Usage: "scale", Min: "0.5"},
+	})
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "--help"})
+	if !strings.Contains(out.String(), "(Range: 1..10)") || !strings.Contains(out.String(), "(Min: 0.5)") {
+		t.Errorf("unexpected help output %s", out.String())
+	}
+}
+
+func TestApplication_ValueErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    IFlag
+		args    []string
+		wantErr string
+		wantMsg string
+	}{
+		{name: "bytes", flag: &Flag[Bytes]{Name: "size"}, args: []string{"test", "--size", "1.5"}, wantErr: "InvalidBytesFormat", wantMsg: "use number with optional unit"},
+		{name: "bytes overflow", flag: &Flag[Bytes]{Name: "size", Short: 's'}, args: []string{"test", "-s", "99999999999PiB"}, wantErr: "InvalidBytesFormat", wantMsg: "invalid size string 99999999999PiB"},
+		{name: "url", flag: &Flag[URL]{Name: "url"}, args: []string{"test", "--url", "::bad"}, wantErr: "InvalidURLFormat", wantMsg: "invalid URL ::bad for flag url"},
+		{name: "regexp", flag: &Flag[Regexp]{Name: "match"}, args: []string{"test", "--match=a("}, wantErr: "InvalidRegexpFormat", wantMsg: "invalid regular expression a("},
+		{name: "cidr", flag: &Flag[CIDR]{Name: "net", Short: 'n'}, args: []string{"test", "-n10.0.0.0/33"}, wantErr: "InvalidCIDRFormat", wantMsg: "invalid CIDR string 10.0.0.0/33"},
+		{name: "semver", flag: &Flag[Semver]{Name: "version"}, args: []string{"test", "--version", "1.x"}, wantErr: "InvalidSemverFormat", wantMsg: "invalid semantic version 1.x"},
+		{name: "timestamp", flag: &Flag[TimeStamp]{Name: "since", Layouts: []string{"2006-01-02"}}, args: []string{"test", "--since", "01/02/2006"}, wantErr: "InvalidTimeFormat", wantMsg: `use one of layouts "2006-01-02"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.AddFlag(tt.flag)
+			errors_out := bytes.NewBuffer(nil)
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(errors_out)
+			err := app.Run(tt.args)
+			var int_err *i18n.Error
+			if !errors.As(err, &int_err) || int_err.GetKey() != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, want %s", err, tt.wantErr)
+			}
+			if !strings.Contains(errors_out.String(), tt.wantMsg) {
+				t.Errorf("unexpected error output %q", errors_out.String())
+			}
+		})
+	}
+}
+
+type testLevel int
+
+const (
+	testLevelDebug testLevel = iota
+	testLevelInfo
+	testLevelWarn
+)
+
+func TestApplication_Enum(t *testing.T) {
+
+	levels := Enum[testLevel]{
+		{Name: "debug", Value: testLevelDebug, Description: "verbose output"},
+		{Name: "info", Value: testLevelInfo, Description: "normal output"},
+		{Name: "warn", Value: testLevelWarn},
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		ignoreCase bool
+		want       testLevel
+		wantSlice  []testLevel
+		wantErr    bool
+	}{
+		{name: "default", args: []string{"test", "run"}, want: testLevelInfo, wantSlice: []testLevel{}},
+		{name: "value", args: []string{"test", "run", "--level", "warn"}, want: testLevelWarn, wantSlice: []testLevel{}},
+		{name: "cumulative", args: []string{"test", "run", "--levels", "warn,debug"}, want: testLevelInfo, wantSlice: []testLevel{testLevelWarn, testLevelDebug}},
+		{name: "case sensitive", args: []string{"test", "run", "--level", "WARN"}, wantErr: true},
+		{name: "ignore case", args: []string{"test", "run", "--level", "WARN"}, ignoreCase: true, want: testLevelWarn, wantSlice: []testLevel{}},
+		{name: "unknown", args: []string{"test", "run", "--level", "trace"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.AddFlags([]IFlag{
+				&Flag[OneOf]{Name: "level", Enum: levels, Default: "info", IgnoreCase: tt.ignoreCase},
+				&Flag[[]String]{Name: "levels", Enum: levels},
+			})
+			app.AddCommand(Command{Name: "run"})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if level, _ := app.GetFlagValue("level"); level != tt.want {
+					t.Errorf("level = %v, want %v", level, tt.want)
+				}
+				if level_list, _ := app.GetFlagValue("levels"); !reflect.DeepEqual(level_list, tt.wantSlice) {
+					t.Errorf("levels = %v, want %v", level_list, tt.wantSlice)
+				}
+			}
+		})
+	}
+
+	// entry with nil value of interface typed enum
+	optional := Enum[any]{{Name: "none", Value: nil}, {Name: "one", Value: 1}}
+	app := New()
+	app.AddFlags([]IFlag{
+		&Flag[OneOf]{Name: "optional", Enum: optional},
+		&Flag[[]String]{Name: "optionals", Enum: optional},
+	})
+	app.AddCommand(Command{Name: "run"})
+	app.SetWriter(bytes.NewBuffer(nil))
+	app.SetErrorWriter(bytes.NewBuffer(nil))
+	if err := app.Run([]string{"test", "run", "--optional", "none", "--optionals", "none,one"}); err != nil {
+		t.Fatalf("Application.Run() error = %v", err)
+	}
+	if value, _ := app.GetFlagValue("optional"); value != nil {
+		t.Errorf("optional = %v, want nil", value)
+	}
+	if values, _ := app.GetFlagValue("optionals"); !reflect.DeepEqual(values, []any{nil, 1}) {
+		t.Errorf("optionals = %v, want [<nil> 1]", values)
+	}
+
+	// completion offers values with descriptions
+	got := hintCompletions(&Flag[OneOf]{Name: "level", Enum: levels}, "")
+	want := []Completion{{Value: "debug", Description: "verbose output"}, {Value: "info", Description: "normal output"}, {Value: "warn"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hintCompletions() = %q, want %q", got, want)
+	}
+	got = hintCompletions(&Flag[OneOf]{Name: "level", Enum: levels, IgnoreCase: true}, "D")
+	want = []Completion{{Value: "debug", Description: "verbose output"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hintCompletions() = %q, want %q", got, want)
+	}
+
+	// help shows values table
+	app = New()
+	app.AddFlag(&Flag[OneOf]{Name: "level", Usage: "log level", Enum: levels})
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "--help"})
+	if !strings.Contains(out.String(), "| debug | verbose output |") {
+		t.Errorf("unexpected help output %s", out.String())
+	}
+	// table is indented as flag usage
+	usage_indent, table_indent := "", ""
+	for _, line := range strings.Split(out.String(), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "log level") {
+			usage_indent = line[:len(line)-len(trimmed)]
+		}
+		if strings.HasPrefix(trimmed, "| debug") {
+			table_indent = line[:len(line)-len(trimmed)]
+		}
+	}
+	if usage_indent == "" || table_indent != usage_indent {
+		t.Errorf("table indent %q, want usage indent %q in %s", table_indent, usage_indent, out.String())
+	}
+}
+
+// user defined value type
+type testColor struct {
+	R, G, B uint8
+}
+
+func (c *testColor) FromString(v string, fa IFlagArg) error {
+	if _, err := fmt.Sscanf(v, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return fmt.Errorf("invalid color %s", v)
+	}
+	return nil
+}
+
+func (c *testColor) GetValue() interface{} {
+	return *c
+}
+
+func (c *testColor) GetReturnType() reflect.Type {
+	return reflect.TypeOf(testColor{})
+}
+
+func (c *testColor) Complete(prefix string, fa IFlagArg) []Completion {
+	return []Completion{{Value: "#000000", Description: "black"}, {Value: "#ffffff", Description: "white"}}
+}
+
+func TestApplication_CustomTypes(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    testColor
+		wantAll []testColor
+		wantErr bool
+	}{
+		{name: "default", args: []string{"test", "run"}, want: testColor{255, 255, 255}, wantAll: []testColor{}},
+		{name: "value", args: []string{"test", "run", "--color", "#102030"}, want: testColor{16, 32, 48}, wantAll: []testColor{}},
+		{
+			name:    "cumulative",
+			args:    []string{"test", "run", "--palette", "#000000,#ff0000", "--palette", "#00ff00"},
+			want:    testColor{255, 255, 255},
+			wantAll: []testColor{{0, 0, 0}, {255, 0, 0}, {0, 255, 0}},
+		},
+		{name: "invalid", args: []string{"test", "run", "--color", "red"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var color testColor
+			app := New()
+			app.AddFlags([]IFlag{
+				&Flag[Value[*testColor]]{Name: "color", Default: "#ffffff", Destination: NewValue(&color)},
+				&Flag[Values[*testColor]]{Name: "palette"},
+			})
+			app.AddCommand(Command{Name: "run"})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got, _ := app.GetFlagValue("color"); got != tt.want || color != tt.want {
+					t.Errorf("color = %v, destination = %v, want %v", got, color, tt.want)
+				}
+				if got, _ := app.GetFlagValue("palette"); !reflect.DeepEqual(got, tt.wantAll) {
+					t.Errorf("palette = %v, want %v", got, tt.wantAll)
+				}
+			}
+		})
+	}
+
+	// value type completes flag without hints
+	got := hintCompletions(&Flag[Value[*testColor]]{Name: "color"}, "#f")
+	if !reflect.DeepEqual(got, []Completion{{Value: "#ffffff", Description: "white"}}) {
+		t.Errorf("hintCompletions() = %q", got)
+	}
+
+	// typed access to values
+	palette := &Values[*testColor]{}
+	app := New()
+	app.AddFlag(&Flag[Values[*testColor]]{Name: "palette", Destination: palette})
+	app.AddCommand(Command{Name: "run", Args: []IArg{&Arg[Value[*testColor]]{Name: "background"}}})
+	app.SetWriter(bytes.NewBuffer(nil))
+	app.SetErrorWriter(bytes.NewBuffer(nil))
+	if err := app.Run([]string{"test", "--palette", "#010203", "run", "#040506"}); err != nil {
+		t.Fatalf("Application.Run() error = %v", err)
+	}
+	if got := palette.Get(); len(got) != 1 || *got[0] != (testColor{1, 2, 3}) {
+		t.Errorf("palette = %v", got)
+	}
+	if got, _ := app.GetArgumentValue("background"); got != (testColor{4, 5, 6}) {
+		t.Errorf("background = %v", got)
+	}
+}
+
+func TestApplication_Constraints(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "nothing set", args: []string{"test", "deploy", "--file", "a.yaml"}},
+		{name: "requires", args: []string{"test", "deploy", "--file", "a.yaml", "--tls-cert", "c.pem"}, wantErr: "ConstraintRequires"},
+		{name: "requires satisfied", args: []string{"test", "deploy", "--file", "a.yaml", "--tls-cert", "c.pem", "--tls-key", "k.pem"}},
+		{name: "conflicts", args: []string{"test", "deploy", "--file", "a.yaml", "--json", "--yaml"}, wantErr: "ConstraintConflicts"},
+		{name: "at least one of", args: []string{"test", "deploy"}, wantErr: "ConstraintAtLeastOneOf"},
+		{name: "at least one of argument", args: []string{"test", "deploy", "app.yaml"}},
+		{name: "all or none", args: []string{"test", "deploy", "--file", "a.yaml", "--user", "bob"}, wantErr: "ConstraintAllOrNone"},
+		{name: "all", args: []string{"test", "deploy", "--file", "a.yaml", "--user", "bob", "--password", "x"}},
+		{name: "required if", args: []string{"test", "deploy", "--file", "a.yaml", "--cloud", "aws"}, wantErr: "ConstraintRequiredIf"},
+		{name: "required if ignore case", args: []string{"test", "deploy", "--file", "a.yaml", "--cloud", "AWS"}, wantErr: "ConstraintRequiredIf"},
+		{name: "required if satisfied", args: []string{"test", "deploy", "--file", "a.yaml", "--cloud", "aws", "--region", "eu"}},
+		{name: "required if other value", args: []string{"test", "deploy", "--file", "a.yaml", "--cloud", "gcp"}},
+		{name: "required if parsed value", args: []string{"test", "deploy", "--file", "a.yaml", "--timeout", "60s"}, wantErr: "ConstraintRequiredIf"},
+		// rule of application names flags defined only on application, it is checked when sub-command runs
+		{name: "parent constraint", args: []string{"test", "deploy", "--file", "a.yaml", "--quiet", "--verbose"}, wantErr: "ConstraintConflicts"},
+		{name: "parent constraint satisfied", args: []string{"test", "deploy", "--file", "a.yaml", "--quiet"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newConstraintsApp()
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Application.Run() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("Application.Run() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+
+	// help lists the rules under synopsis
+	app := newConstraintsApp()
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "deploy", "--help"})
+	for _, want := range []string{
+		"--tls-cert requires --tls-key",
+		"--json cannot be used together with --yaml",
+		"at least one of --file, MANIFEST is required",
+		"--user, --password must be used together",
+		"--region is required when --cloud is aws",
+		"--quiet cannot be used together with --verbose",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	// misspelled names are definition errors
+	misspelled := []Constraints{
+		{Requires: map[string][]string{"tls-cert": {"tls-kye"}}},
+		{ConflictsWith: map[string][]string{"jsno": {"yaml"}}},
+		{AtLeastOneOf: [][]string{{"file", "manifets"}}},
+		{AllOrNone: [][]string{{"usr", "password"}}},
+		{RequiredIf: []RequiredIf{{Name: "region", If: "clod", Value: "aws"}}},
+	}
+	for _, constraints := range misspelled {
+		app := newConstraintsApp()
+		app.Commands[0].Constraints = constraints
+		errors_out := bytes.NewBuffer(nil)
+		app.SetWriter(bytes.NewBuffer(nil))
+		app.SetErrorWriter(errors_out)
+		err := app.Run([]string{"test", "deploy", "--file", "a.yaml"})
+		if err == nil || err.Error() != "ConstraintUnknownName" {
+			t.Fatalf("Application.Run() error = %v, want ConstraintUnknownName for %+v", err, constraints)
+		}
+		if !strings.Contains(errors_out.String(), "constraints of command deploy refer to unknown flag or argument") {
+			t.Errorf("unexpected error output %q", errors_out.String())
+		}
+	}
+
+	// rule of parent can name flag of sub-command
+	app = newConstraintsApp()
+	app.Constraints.Requires = map[string][]string{"verbose": {"file"}}
+	app.SetWriter(bytes.NewBuffer(nil))
+	app.SetErrorWriter(bytes.NewBuffer(nil))
+	if err := app.Run([]string{"test", "deploy", "manifest.yaml", "--verbose"}); err == nil || err.Error() != "ConstraintRequires" {
+		t.Errorf("Application.Run() error = %v, want ConstraintRequires", err)
+	}
+}
+
+func newConstraintsApp() *Application {
+	app := New()
+	app.AddFlags([]IFlag{
+		&Flag[Bool]{Name: "quiet"},
+		&Flag[Bool]{Name: "verbose"},
+	})
+	app.Constraints = Constraints{ConflictsWith: map[string][]string{"quiet": {"verbose"}}}
+	app.AddCommand(Command{
+		Name: "deploy",
+		Flags: []IFlag{
+			&Flag[String]{Name: "file"},
+			&Flag[String]{Name: "tls-cert"},
+			&Flag[String]{Name: "tls-key"},
+			&Flag[Bool]{Name: "json"},
+			&Flag[Bool]{Name: "yaml"},
+			&Flag[String]{Name: "user"},
+			&Flag[String]{Name: "password"},
+			&Flag[String]{Name: "cloud", IgnoreCase: true},
+			&Flag[String]{Name: "region"},
+			&Flag[Duration]{Name: "timeout", Default: "30s"},
+			&Flag[String]{Name: "retries"},
+		},
+		Args: []IArg{&Arg[String]{Name: "manifest"}},
+		Constraints: Constraints{
+			Requires:      map[string][]string{"tls-cert": {"tls-key"}},
+			ConflictsWith: map[string][]string{"json": {"yaml"}},
+			AtLeastOneOf:  [][]string{{"file", "manifest"}},
+			AllOrNone:     [][]string{{"user", "password"}},
+			RequiredIf: []RequiredIf{
+				{Name: "region", If: "cloud", Value: "aws"},
+				{Name: "retries", If: "timeout", Value: "1m"},
+			},
+		},
+	})
+	return app
+}
+
+func TestApplication_ReportAllErrors(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		args       []string
+		all        bool
+		wantKeys   []string
+		wantOutput []string
+	}{
+		{name: "fail fast", args: []string{"test", "run", "--port", "0"}, wantKeys: []string{"ValueBelowMin"}},
+		{
+			name:     "all errors",
+			args:     []string{"test", "run", "--port", "0", "--json", "--yaml"},
+			all:      true,
+			wantKeys: []string{"ValueBelowMin", "MissingRequiredArg", "MissingRequiredFlag", "ConstraintConflicts", "command failed"},
+			wantOutput: []string{
+				"5 validation errors:",
+				"  - value 0 of flag port is less than minimum 1",
+				"  - required argument TARGET is missing",
+				"  - --json cannot be used together with --yaml",
+			},
+		},
+		{name: "no errors", args: []string{"test", "run", "--name", "x", "--port", "80", "target"}, all: true},
+		{
+			name:     "group conflict",
+			args:     []string{"test", "auth", "--user", "u", "--token", "t"},
+			all:      true,
+			wantKeys: []string{"FlagsArgsFromMultipleGroups", "MissingRequiredFlag", "MissingRequiredFlag"},
+			wantOutput: []string{
+				"3 validation errors:",
+				"  - required flag --realm is missing",
+				"  - required flag --server is missing",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.ReportAllErrors = tt.all
+			app.AddCommand(Command{
+				Name: "run",
+				Flags: []IFlag{
+					&Flag[Int]{Name: "port", Min: "1"},
+					&Flag[String]{Name: "name", Required: true},
+					&Flag[Bool]{Name: "json"},
+					&Flag[Bool]{Name: "yaml"},
+				},
+				Args:        []IArg{&Arg[String]{Name: "target", Required: true}},
+				Constraints: Constraints{ConflictsWith: map[string][]string{"json": {"yaml"}}},
+				Validator: func(a *Application, c *Command) error {
+					if v, _ := a.GetFlagValue("port"); v.(int) < 1 {
+						return errors.New("command failed")
+					}
+					return nil
+				},
+			})
+			// required flags outside of groups or common to all of them are checked on group conflict
+			app.AddCommand(Command{
+				Name: "auth",
+				Flags: []IFlag{
+					&Flag[String]{Name: "user", Required: true, ValidationGroups: []string{"login"}},
+					&Flag[String]{Name: "password", Required: true, ValidationGroups: []string{"login"}},
+					&Flag[String]{Name: "token", Required: true, ValidationGroups: []string{"token"}},
+					&Flag[String]{Name: "realm", Required: true, ValidationGroups: []string{"login", "token"}},
+					&Flag[String]{Name: "server", Required: true},
+				},
+			})
+			app.SetWriter(bytes.NewBuffer(nil))
+			errs := bytes.NewBuffer(nil)
+			app.SetErrorWriter(errs)
+			err := app.Run(tt.args)
+			if len(tt.wantKeys) == 0 {
+				if err != nil {
+					t.Fatalf("Application.Run() error = %v", err)
+				}
+				return
+			}
+			if ExitCode(err) != ExitUsage {
+				t.Errorf("ExitCode() = %d, want %d", ExitCode(err), ExitUsage)
+			}
+			var all_errs ValidationErrors
+			if !tt.all {
+				if errors.As(err, &all_errs) || err.Error() != tt.wantKeys[0] {
+					t.Fatalf("Application.Run() error = %v, want %s", err, tt.wantKeys[0])
+				}
+				return
+			}
+			if !errors.As(err, &all_errs) {
+				t.Fatalf("Application.Run() error = %v, want ValidationErrors", err)
+			}
+			keys := make([]string, 0)
+			for _, e := range all_errs {
+				keys = append(keys, e.Error())
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("errors = %v, want %v", keys, tt.wantKeys)
+			}
+			// errors.Is and errors.As look into the list
+			var first *i18n.Error
+			if !errors.As(err, &first) || first.GetKey() != tt.wantKeys[0] {
+				t.Errorf("errors.As() = %v, want %s", first, tt.wantKeys[0])
+			}
+			if !errors.Is(err, all_errs[len(all_errs)-1]) {
+				t.Errorf("errors.Is() does not find %v", all_errs[len(all_errs)-1])
+			}
+			// missing element is kept in error
+			for _, e := range all_errs {
+				var int_err *i18n.Error
+				if errors.As(e, &int_err) && strings.HasPrefix(int_err.GetKey(), "MissingRequired") {
+					if _, ok := int_err.GetData().(IFlagArg); !ok {
+						t.Errorf("error does not keep its element: %v", e)
+					}
+				}
+			}
+			// every error is listed
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(errs.String(), want) {
+					t.Errorf("error output does not contain %q:\n%s", want, errs.String())
+				}
+			}
+		})
+	}
+}
+
+// answers questions in order and remembers them
+type testPrompter struct {
+	answers   []string
+	questions []string
+	secret    []bool
+	err       error // returned when answers run out, io.EOF if not set
+}
+
+func (p *testPrompter) Prompt(question string, secret bool) (string, error) {
+	p.questions = append(p.questions, question)
+	p.secret = append(p.secret, secret)
+	if len(p.answers) == 0 && p.err != nil {
+		return "", p.err
+	}
+	if len(p.answers) == 0 {
+		return "", io.EOF
+	}
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer, nil
+}
+
+func TestApplication_PromptForMissing(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		prompt        bool
+		args          []string
+		answers       []string
+		wantQuestions []string
+		wantSecret    []bool
+		wantName      string
+		wantFormat    string
+		wantToken     string
+		promptErr     error
+		wantErr       string
+	}{
+		{name: "disabled", args: []string{"test", "run"}, wantErr: "MissingRequiredArg"},
+		{name: "nothing missing", prompt: true, args: []string{"test", "run", "bob", "--format", "yaml", "--token", "t"}, wantName: "bob", wantFormat: "yaml", wantToken: "t"},
+		{
+			name:          "all missing",
+			prompt:        true,
+			args:          []string{"test", "run"},
+			answers:       []string{" bob ", "2", "s3cr3t"},
+			wantQuestions: []string{"Enter NAME (user name): ", "--format (output format):\n  1) json - JSON document\n  2) yaml - YAML document\nChoose number or value [json]: ", "Enter --token (API token): "},
+			wantSecret:    []bool{false, false, true},
+			wantName:      "bob",
+			wantFormat:    "yaml",
+			wantToken:     "s3cr3t",
+		},
+		{name: "default and value", prompt: true, args: []string{"test", "run", "bob"}, answers: []string{"", "t"}, wantName: "bob", wantFormat: "json", wantToken: "t"},
+		{name: "invalid answer asked again", prompt: true, args: []string{"test", "run", "bob", "--token", "t"}, answers: []string{"xml", "yaml"}, wantName: "bob", wantFormat: "yaml", wantToken: "t"},
+		{name: "invalid answers", prompt: true, args: []string{"test", "run", "bob", "--token", "t"}, answers: []string{"xml", "7", "csv"}, wantErr: "UnknownEnumValue"},
+		{name: "no answer", prompt: true, args: []string{"test", "run", "bob", "--format", "yaml"}, answers: []string{""}, wantErr: "MissingRequiredFlag"},
+		{name: "input closed", prompt: true, args: []string{"test", "run", "--format", "yaml", "--token", "t"}, wantErr: "MissingRequiredArg"},
+		{name: "prompt failed", prompt: true, args: []string{"test", "run", "bob", "--format", "yaml"}, promptErr: errors.New("broken"), wantErr: "PromptFailed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompter := &testPrompter{answers: tt.answers, err: tt.promptErr}
+			app := New()
+			app.PromptForMissing = tt.prompt
+			app.Prompter = prompter
+			app.AddCommand(Command{
+				Name: "run",
+				Args: []IArg{&Arg[String]{Name: "name", Usage: "user name", Required: true}},
+				Flags: []IFlag{
+					&Flag[String]{
+						Name:     "format",
+						Usage:    "output format",
+						Default:  "json",
+						Required: true,
+						Enum:     Enum[string]{{Name: "json", Value: "json", Description: "JSON document"}, {Name: "yaml", Value: "yaml", Description: "YAML document"}},
+					},
+					&Flag[String]{Name: "token", Usage: "API token", Required: true, Secret: true},
+				},
+			})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Application.Run() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Application.Run() error = %v", err)
+			}
+			if tt.wantQuestions != nil && !reflect.DeepEqual(prompter.questions, tt.wantQuestions) {
+				t.Errorf("questions = %q, want %q", prompter.questions, tt.wantQuestions)
+			}
+			if tt.wantSecret != nil && !reflect.DeepEqual(prompter.secret, tt.wantSecret) {
+				t.Errorf("secret = %v, want %v", prompter.secret, tt.wantSecret)
+			}
+			name, _ := app.GetArgumentValue("name")
+			format, _ := app.GetFlagValue("format")
+			token, _ := app.GetFlagValue("token")
+			if name != tt.wantName || format != tt.wantFormat || token != tt.wantToken {
+				t.Errorf("values = %v, %v, %v, want %v, %v, %v", name, format, token, tt.wantName, tt.wantFormat, tt.wantToken)
+			}
+		})
+	}
+}
+
+func TestApplication_Completer(t *testing.T) {
+
+	clusters := func(a *Application, prefix string) ([]Completion, error) {
+		return []Completion{{Value: "prod-eu", Description: "production"}, {Value: "prod-us"}, {Value: "staging"}}, nil
+	}
+	failing := func(a *Application, prefix string) ([]Completion, error) {
+		return []Completion{{Value: "x"}}, errors.New("cache not found")
+	}
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "flag value", args: []string{"test", "__complete", "deploy", "--cluster", "prod"}, want: "prod-eu\tproduction\nprod-us\n:0\n"},
+		{name: "flag value all", args: []string{"test", "__complete", "deploy", "--cluster", ""}, want: "prod-eu\tproduction\nprod-us\nstaging\n:0\n"},
+		{
+			name: "argument and command",
+			args: []string{"test", "__complete", "deploy", ""},
+			want: "status\nlogs\nprod-eu\tproduction\nprod-us\nstaging\n:0\n",
+		},
+		{name: "failed callback", args: []string{"test", "__complete", "deploy", "--branch", ""}, want: ":0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.ShellCompletion = true
+			app.AddCommand(Command{
+				Name: "deploy",
+				Flags: []IFlag{
+					&Flag[String]{Name: "cluster", Completer: clusters},
+					&Flag[String]{Name: "branch", Hints: []string{"main"}, Completer: failing},
+				},
+				Args: []IArg{&Arg[String]{Name: "target", Completer: clusters}},
+				Completer: func(a *Application, prefix string) ([]Completion, error) {
+					return []Completion{{Value: "status"}, {Value: "logs"}}, nil
+				},
+			})
+			out := bytes.NewBuffer(nil)
+			app.SetWriter(out)
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			if err := app.Run(tt.args); err != nil {
+				t.Fatalf("Application.Run() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("completion = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
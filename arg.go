@@ -1,11 +1,17 @@
 package gocli
 
-import "strings"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
 
 type IArg interface {
 	IFlagArg
 }
 
+// TArg is value type of argument: TArgFlag types
 type TArg interface {
 	TArgFlag
 }
@@ -20,8 +26,22 @@ type Arg[T TArg] struct {
 	Placeholder      string
 	Required         bool
 	Destination      *T
-	Hidden           bool // can be used on command line but will not show on help
+	Hidden           bool           // can be used on command line but will not show on help
+	EnvVars          []string       // environment variables to take value from if argument is not set on command line; first one set wins
+	Min              string         // minimal value of numeric argument, parsed as argument value
+	Max              string         // maximal value of numeric argument, parsed as argument value
+	Enum             IEnum          // allowed values of argument, GetValue returns Go value of matched entry
+	IgnoreCase       bool           // match hints and enum values ignoring case
+	PathMode         PathMode       // requirements for value of File, Dir and Path argument
+	Extensions       []string       // allowed extensions of File, Dir and Path argument, i.e. .yaml
+	Layouts          []string       // layouts of TimeStamp argument used instead of default ones
+	Location         *time.Location // time zone of TimeStamp argument value without zone, UTC if not set
+	Secret           bool           // value is not echoed when prompted for
+	Completer        CompletionFunc // offers shell completion candidates for value of argument instead of hints
 	isSetByUser      bool
+	source           ValueSource
+	ownDestination   bool          // destination was created by gocli, not provided by user
+	structField      reflect.Value // struct field bound by FromStruct, value is copied to it when set
 	ValidationGroups []string
 	Validator        ArgValidator
 }
@@ -38,8 +58,58 @@ func (a *Arg[T]) GetUsage() string {
 	return a.Usage
 }
 func (a *Arg[T]) GetHints() []string {
+	if len(a.Hints) == 0 && a.Enum != nil {
+		return a.Enum.GetNames()
+	}
 	return a.Hints
 }
+func (a *Arg[T]) GetEnum() IEnum {
+	return a.Enum
+}
+func (a *Arg[T]) IsIgnoreCase() bool {
+	return a.IgnoreCase
+}
+func (a *Arg[T]) IsSecret() bool {
+	return a.Secret
+}
+
+// String returns argument with its value for logging and debugging; value of secret argument is redacted
+func (a *Arg[T]) String() string {
+	if a.Destination == nil || a.source == SourceNone {
+		return a.GetPlaceholder()
+	}
+	return a.GetPlaceholder() + "=" + redactValue(a, fmt.Sprint(a.GetValue()))
+}
+func (a *Arg[T]) GetPathMode() PathMode {
+	return a.PathMode
+}
+func (a *Arg[T]) GetExtensions() []string {
+	return a.Extensions
+}
+func (a *Arg[T]) GetLayouts() []string {
+	return a.Layouts
+}
+func (a *Arg[T]) GetLocation() *time.Location {
+	return a.Location
+}
+func (a *Arg[T]) GetEnvVars() []string {
+	return a.EnvVars
+}
+func (a *Arg[T]) GetCompleter() CompletionFunc {
+	return a.Completer
+}
+func (a *Arg[T]) GetMin() string {
+	return a.Min
+}
+func (a *Arg[T]) GetMax() string {
+	return a.Max
+}
+func (a *Arg[T]) GetSource() ValueSource {
+	return a.source
+}
+func (a *Arg[T]) setSource(source ValueSource) {
+	a.source = source
+}
 func (a *Arg[T]) IsRequired() bool {
 	return a.Required
 }
@@ -65,13 +135,16 @@ func (a *Arg[T]) GetDefault() string {
 func (a *Arg[T]) getDestination() interface{} {
 	if a.Destination == nil {
 		a.Destination = new(T)
+		a.ownDestination = true
 	}
 	return a.Destination
 }
 
 func (a *Arg[T]) GetPlaceholder() string {
 	p := a.Placeholder
-	if p == "" {
+	if p == "" && isMap(a) {
+		p = mapPlaceholder
+	} else if p == "" {
 		p = a.Name
 	}
 	return strings.ToUpper(p)
@@ -102,12 +175,27 @@ func (a *Arg[T]) SetValue(value string) error {
 		}
 	}
 	a.SetByUser()
+	a.source = SourceCommandLine
 	return nil
 }
 
 func (a *Arg[T]) Clear() {
 	a.isSetByUser = false
-	a.Destination = new(T)
+	a.source = SourceNone
+	// destination provided by user is left alone, struct field bound by FromStruct is reset
+	if a.Destination == nil || a.ownDestination {
+		a.Destination = new(T)
+		a.ownDestination = true
+	}
+	if a.structField.IsValid() {
+		a.structField.Set(reflect.Zero(a.structField.Type()))
+	}
+}
+
+func (a *Arg[T]) copyToField() {
+	if a.structField.IsValid() {
+		copyToField(a.structField, reflect.ValueOf(a.getDestination()).Elem())
+	}
 }
 
 func (a *Arg[T]) SetByUser() {
@@ -119,8 +207,12 @@ func (a *Arg[T]) GetValue() interface{} {
 }
 
 func (a *Arg[T]) ValidateWrapper(app *Application) error {
+	// range is checked before custom validator, value of secret argument is hidden in errors of both
+	if err := checkRange(a); err != nil {
+		return redactError(a, err)
+	}
 	if a.Validator != nil {
-		return a.Validator(app, a)
+		return redactError(a, a.Validator(app, a))
 	}
 	return nil
 }
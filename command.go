@@ -1,6 +1,7 @@
 package gocli
 
 import (
+	gocontext "context"
 	"reflect"
 	"strings"
 
@@ -8,6 +9,11 @@ import (
 )
 
 type Action func(*Application, *Command, interface{}) (interface{}, error)
+
+// ContextAction is an action that receives context of the run. Context is cancelled when application
+// is interrupted if Application.HandleSignals is set
+type ContextAction func(gocontext.Context, *Application, *Command, interface{}) (interface{}, error)
+
 type CommandValidator func(*Application, *Command) error
 
 type Command struct {
@@ -20,8 +26,11 @@ type Command struct {
 	Args             []IArg
 	Commands         []*Command
 	Action           Action
+	ContextAction    ContextAction // used instead of Action if set
 	Validator        CommandValidator
 	ValidationGroups []string
+	Constraints      Constraints    // rules between flags and arguments, i.e. one flag requires another
+	Completer        CompletionFunc // offers shell completion candidates in addition to sub-commands, arguments and flags
 	Optional         bool
 	Hidden           bool // can be used on command line but will not show on help
 	initialized      bool
@@ -294,11 +303,32 @@ func (c *Command) ValidateWrapper(app *Application) error {
 	return nil
 }
 
+// ActionWrapper runs action of command with context of current run
 func (c *Command) ActionWrapper(app *Application, in_data interface{}) (interface{}, error) {
+	ctx := app.runContext
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+	return c.runAction(ctx, app, in_data)
+}
+
+// runs ContextAction if set, Action otherwise
+func (c *Command) runAction(ctx gocontext.Context, app *Application, in_data interface{}) (interface{}, error) {
 	var data interface{} = nil
 	var err error = nil
-	if c.Action != nil {
-		data, err = c.Action(app, app.context.CurrentCommand, in_data)
+	action := c.ContextAction
+	if action == nil && c.Action != nil {
+		action = c.Action.WithContext()
+	}
+	if action != nil {
+		data, err = action(ctx, app, app.context.CurrentCommand, in_data)
 	}
 	return data, err
 }
+
+// WithContext adapts Action to ContextAction that ignores context
+func (action Action) WithContext() ContextAction {
+	return func(_ gocontext.Context, app *Application, cmd *Command, data interface{}) (interface{}, error) {
+		return action(app, cmd, data)
+	}
+}
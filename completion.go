@@ -0,0 +1,337 @@
+package gocli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/ez-leka/gocli/i18n"
+	"golang.org/x/exp/slices"
+)
+
+// CompletionDirective tells completion script how to complete the word, directives can be combined
+type CompletionDirective int
+
+const (
+	CompletionNoSpace CompletionDirective = 1 << iota // no space is added after completed word, i.e. after --flag=
+	CompletionFiles                                   // shell completes file names; candidates, if any, are allowed extensions
+	CompletionDirs                                    // shell completes directory names
+)
+
+// hidden first argument of completion request; not localizable - completion scripts depend on it
+const completeCommand = "__complete"
+
+// shells with completion script template <Shell>CompletionTemplate
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// value types that are better completed by shell itself, i.e. file names
+type shellCompleted interface {
+	shellCompletion(fa IFlagArg) ([]Completion, CompletionDirective)
+}
+
+// files and directories are completed by shell and checked on file system when parsed
+func isPathValue(fa IFlagArg) bool {
+	_, ok := newSingleValue(fa).(shellCompleted)
+	return ok
+}
+
+// answers completion request of completion scripts: candidates for the last word of args are written one per line
+// with description after tab, followed by line with directive, i.e. ":2"
+func (a *Application) complete(args []string) {
+	current := ""
+	if len(args) > 0 {
+		current = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+	// words before current one are parsed to find command and flags already set;
+	// errors do not matter - completion works with whatever was parsed
+	a.context.completing = true
+	defer func() { a.context.completing = false }()
+	a.context.parse(a, args)
+
+	prev := ""
+	if len(args) > 0 {
+		prev = args[len(args)-1]
+	}
+	candidates, directive := a.context.completeWord(a, prev, current)
+	for _, c := range candidates {
+		if strings.HasSuffix(c.Value, "=") || strings.HasSuffix(c.Value, "/") || strings.HasSuffix(c.Value, string(os.PathSeparator)) {
+			directive |= CompletionNoSpace
+		}
+		if c.Description != "" {
+			fmt.Fprintf(a.usageWriter, "%s\t%s\n", c.Value, strings.Join(strings.Fields(c.Description), " "))
+		} else {
+			fmt.Fprintln(a.usageWriter, c.Value)
+		}
+	}
+	fmt.Fprintf(a.usageWriter, ":%d\n", directive)
+}
+
+// writes completion script for shell to output or, if install is set, to per-user completion directory of shell
+func (a *Application) writeCompletion(output io.Writer, shell string, install bool) error {
+	if !slices.Contains(completionShells, shell) {
+		return i18n.NewError("ShellCompletionUnknownShell", TokenTemplateContext{Extra: shell})
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := a.GenerateBashCompletion(buf, shell); err != nil {
+		return err
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	if !install {
+		_, err := output.Write(buf.Bytes())
+		return err
+	}
+
+	path, err := completionInstallPath(a.Name, shell)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			err = os.WriteFile(path, buf.Bytes(), 0644)
+		}
+	}
+	if err != nil {
+		return i18n.NewError("ShellCompletionInstallFailed", TokenTemplateContext{Name: path, Extra: err.Error()})
+	}
+	fmt.Fprintln(a.usageWriter, a.templateManager.GetLocalizedString("ShellCompletionInstalled", path))
+	return nil
+}
+
+// file in per-user directory where shell looks for completion scripts; zsh and PowerShell still need it added to
+// fpath or profile
+func completionInstallPath(name string, shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		data = filepath.Join(home, ".local", "share")
+	}
+	config := os.Getenv("XDG_CONFIG_HOME")
+	if config == "" {
+		config = filepath.Join(home, ".config")
+	}
+	switch shell {
+	case "bash":
+		return filepath.Join(data, "bash-completion", "completions", name), nil
+	case "zsh":
+		return filepath.Join(data, "zsh", "site-functions", "_"+name), nil
+	case "fish":
+		return filepath.Join(config, "fish", "completions", name+".fish"), nil
+	default:
+		if runtime.GOOS == "windows" {
+			return filepath.Join(home, "Documents", "PowerShell", "Completions", name+".ps1"), nil
+		}
+		return filepath.Join(config, "powershell", "Completions", name+".ps1"), nil
+	}
+}
+
+// candidates for word being completed; prev is the word before it
+func (ctx *context) completeWord(app *Application, prev string, word string) ([]Completion, CompletionDirective) {
+	if !ctx.argsOnly {
+		if strings.HasPrefix(word, "--") {
+			// value can be given in the same word, i.e. --cluster=prod
+			if name, value, found := strings.Cut(word[2:], "="); found {
+				if flag, ok := ctx.flags_lookup[name]; ok && takesValue(flag) {
+					return valueCompletions(app, flag, value, word[:len(word)-len(value)])
+				}
+				return nil, 0
+			}
+			return ctx.flagCompletions(word), 0
+		}
+		if word == "-" {
+			return ctx.flagCompletions(word), 0
+		}
+		if strings.HasPrefix(word, "-") {
+			// combined short flags, value of the last one can follow it, i.e. -vcprod
+			runes := []rune(word[1:])
+			for i, r := range runes {
+				flag, ok := ctx.flags_lookup[string(r)]
+				if !ok {
+					return nil, 0
+				}
+				if takesValue(flag) && i < len(runes)-1 {
+					value := strings.TrimPrefix(string(runes[i+1:]), "=")
+					return valueCompletions(app, flag, value, word[:len(word)-len(value)])
+				}
+			}
+			return []Completion{{Value: word}}, 0
+		}
+		if flag := ctx.flagExpectingValue(prev); flag != nil {
+			return valueCompletions(app, flag, word, "")
+		}
+	}
+
+	candidates := make([]Completion, 0)
+	if !ctx.noCommands {
+		for _, cmd := range ctx.CurrentCommand.Commands {
+			if !cmd.Hidden && strings.HasPrefix(cmd.Name, word) {
+				// the same text as in help
+				description := strings.Split(app.templateManager.tplFormatTemplate(cmd.Description, cmd), "\n")[0]
+				candidates = append(candidates, Completion{Value: cmd.Name, Description: description})
+			}
+		}
+	}
+	candidates = append(candidates, callCompleter(app, ctx.CurrentCommand.Completer, word, false)...)
+
+	// only the next positional argument is completed
+	for _, arg := range ctx.arguments_lookup {
+		if arg.GetSource() == SourceCommandLine && !arg.IsCumulative() {
+			continue
+		}
+		values, directive := valueCompletions(app, arg, word, "")
+		if directive&(CompletionFiles|CompletionDirs) != 0 {
+			// file names are completed by shell only if there is nothing else to offer
+			if len(candidates) == 0 {
+				return values, directive
+			}
+			break
+		}
+		candidates = append(candidates, values...)
+		break
+	}
+	return candidates, 0
+}
+
+// flags starting with prefix; flags already set are skipped unless they can be repeated
+func (ctx *context) flagCompletions(prefix string) []Completion {
+	flags := make([]IFlag, 0)
+	for name, flag := range ctx.flags_lookup {
+		// flags are listed by name and by short name
+		if name != flag.GetName() || flag.IsHidden() {
+			continue
+		}
+		if flag.IsSetByUser() && !flag.IsCumulative() && !flag.IsCounter() {
+			continue
+		}
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].GetName() < flags[j].GetName()
+	})
+
+	completions := make([]Completion, 0)
+	for _, flag := range flags {
+		names := []string{"--" + flag.GetName()}
+		if flag.IsNegatable() && len(prefix) > 2 {
+			names = append(names, "--"+negatedFlagPrefix+flag.GetName())
+		}
+		if flag.GetShort() != 0 && prefix == "-" {
+			names = append(names, "-"+string(flag.GetShort()))
+		}
+		for _, name := range names {
+			if strings.HasPrefix(name, prefix) {
+				completions = append(completions, Completion{Value: name, Description: flag.GetUsage()})
+			}
+		}
+	}
+	return completions
+}
+
+// flag that needs value given in the next word, i.e. --cluster or -vc
+func (ctx *context) flagExpectingValue(word string) IFlag {
+	if ctx.argsOnly || !strings.HasPrefix(word, "-") || word == "-" || word == "--" {
+		return nil
+	}
+	if strings.HasPrefix(word, "--") {
+		if flag, ok := ctx.flags_lookup[word[2:]]; ok && takesValue(flag) {
+			return flag
+		}
+		return nil
+	}
+	runes := []rune(word[1:])
+	for i, r := range runes {
+		flag, ok := ctx.flags_lookup[string(r)]
+		if !ok {
+			return nil
+		}
+		if takesValue(flag) {
+			// value of short flag can be in the same word
+			if i < len(runes)-1 {
+				return nil
+			}
+			return flag
+		}
+	}
+	return nil
+}
+
+func takesValue(flag IFlag) bool {
+	return !flag.IsBool() && !flag.IsCounter()
+}
+
+// completion candidates for value of flag or argument: Completer of flag or argument if set, hints otherwise;
+// file names are completed by shell. Candidates are prefixed with part of the word before value, i.e. --cluster=
+func valueCompletions(app *Application, fa IFlagArg, value string, prefix string) ([]Completion, CompletionDirective) {
+	var candidates []Completion
+	if completer := fa.GetCompleter(); completer != nil {
+		candidates = callCompleter(app, completer, value, fa.IsIgnoreCase())
+	} else if v, ok := newSingleValue(fa).(shellCompleted); ok && len(fa.GetHints()) == 0 {
+		// shell completes file names relative to value, candidates are extensions
+		return v.shellCompletion(fa)
+	} else {
+		candidates = hintCompletions(fa, value)
+	}
+	for i := range candidates {
+		candidates[i].Value = prefix + candidates[i].Value
+	}
+	return candidates, 0
+}
+
+// hints of flag or argument starting with prefix with descriptions of enum values;
+// flag or argument without hints is completed by its value type if it implements ICompleter
+func hintCompletions(fa IFlagArg, prefix string) []Completion {
+	candidates := make([]Completion, 0, len(fa.GetHints()))
+	if len(fa.GetHints()) == 0 {
+		// value type can offer its own candidates
+		if completer, ok := newSingleValue(fa).(ICompleter); ok {
+			return filterCompletions(completer.Complete(prefix, fa), prefix, fa.IsIgnoreCase())
+		}
+	}
+	for _, hint := range fa.GetHints() {
+		candidate := Completion{Value: hint}
+		if enum := fa.GetEnum(); enum != nil {
+			candidate.Description = enum.GetDescription(hint)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return filterCompletions(candidates, prefix, fa.IsIgnoreCase())
+}
+
+// candidates of completion callback starting with prefix; failed callback offers nothing
+func callCompleter(app *Application, completer CompletionFunc, prefix string, ignore_case bool) []Completion {
+	if completer == nil {
+		return nil
+	}
+	candidates, err := completer(app, prefix)
+	if err != nil {
+		return nil
+	}
+	return filterCompletions(candidates, prefix, ignore_case)
+}
+
+// candidates starting with prefix
+func filterCompletions(candidates []Completion, prefix string, ignore_case bool) []Completion {
+	filtered := make([]Completion, 0)
+	for _, c := range candidates {
+		if strings.HasPrefix(c.Value, prefix) || (ignore_case && strings.HasPrefix(strings.ToLower(c.Value), strings.ToLower(prefix))) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// allowed extensions of file without leading dot
+func extensionCompletions(fa IFlagArg) []Completion {
+	candidates := make([]Completion, 0)
+	for _, ext := range fa.GetExtensions() {
+		candidates = append(candidates, Completion{Value: strings.TrimPrefix(ext, ".")})
+	}
+	return candidates
+}
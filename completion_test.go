@@ -0,0 +1,169 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+func newCompletionApp() *Application {
+	app := New()
+	app.ShellCompletion = true
+	app.AddFlags([]IFlag{
+		&Flag[Bool]{Name: "verbose", Short: 'v', Usage: "verbose output"},
+		&Flag[OneOf]{Name: "level", Short: 'l', Hints: []string{"debug", "info"}, EnvVars: []string{"TEST_COMPLETE_LEVEL"}},
+	})
+	app.AddCommand(Command{
+		Name:        "deploy",
+		Description: "deploy service\nto the cluster",
+		Usage:       "deploy [service]",
+		Flags: []IFlag{
+			&Flag[File]{Name: "config", Extensions: []string{".yaml", "yml"}},
+			&Flag[Dir]{Name: "out", Short: 'o'},
+			&Flag[map[String]String]{Name: "label", Hints: []string{"team="}},
+		},
+		Args: []IArg{
+			&Arg[OneOf]{Name: "service", Hints: []string{"api", "web"}},
+			&Arg[File]{Name: "manifest"},
+		},
+	})
+	app.AddCommand(Command{Name: "debug", Hidden: true})
+	return app
+}
+
+func TestApplication_Complete(t *testing.T) {
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{name: "commands", args: []string{"d"}, want: "deploy\tdeploy service\n:0\n"},
+		{name: "long flags", args: []string{"--ve"}, want: "--verbose\tverbose output\n:0\n"},
+		{name: "negated flags", args: []string{"--no-v"}, want: "--no-verbose\tverbose output\n:0\n"},
+		{name: "short flags", args: []string{"deploy", "-"}, want: "--config\n--help\tShow context-sensitive help\n-h\tShow context-sensitive help\n--label\n--level\n-l\n--out\n-o\n--verbose\tverbose output\n-v\tverbose output\n:0\n"},
+		{name: "flag already set", args: []string{"-v", "--v"}, want: ":0\n"},
+		{name: "flag value", args: []string{"--level", "d"}, want: "debug\n:0\n"},
+		{name: "flag value in same word", args: []string{"--level=i"}, want: "--level=info\n:0\n"},
+		{name: "short flag value", args: []string{"-vl", ""}, want: "debug\ninfo\n:0\n"},
+		{name: "short flag value in same word", args: []string{"-vld"}, want: "-vldebug\n:0\n"},
+		{name: "short flag", args: []string{"-vl"}, want: "-vl\n:0\n"},
+		{name: "unknown flag", args: []string{"--color="}, want: ":0\n"},
+		{name: "no space", args: []string{"deploy", "--label", ""}, want: "team=\n:1\n"},
+		{name: "files", args: []string{"deploy", "--config", ""}, want: "yaml\nyml\n:2\n"},
+		{name: "files in same word", args: []string{"deploy", "--config=conf"}, want: "yaml\nyml\n:2\n"},
+		{name: "directories", args: []string{"deploy", "-o", ""}, want: ":4\n"},
+		{name: "argument", args: []string{"deploy", "w"}, want: "web\n:0\n"},
+		{name: "next argument", args: []string{"deploy", "api", ""}, want: ":2\n"},
+		{name: "all arguments set", args: []string{"deploy", "api", "go.mod", ""}, want: ":0\n"},
+		// values are not read from environment and files are not checked while completing
+		{name: "environment not applied", args: []string{"--le"}, env: map[string]string{"TEST_COMPLETE_LEVEL": "debug"}, want: "--level\n:0\n"},
+		{name: "file not checked", args: []string{"deploy", "--config", "missing.yaml", "--c"}, want: ":0\n"},
+		{name: "argument file not checked", args: []string{"deploy", "api", "missing.yaml", ""}, want: ":0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			app := newCompletionApp()
+			out := bytes.NewBuffer(nil)
+			app.SetWriter(out)
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			if err := app.Run(append([]string{"test", completeCommand}, tt.args...)); err != nil {
+				t.Fatalf("Application.Run() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("completion = %q, want %q", out.String(), tt.want)
+			}
+			if app.context.completing {
+				t.Errorf("completing is not cleared")
+			}
+		})
+	}
+}
+
+func TestApplication_GenerateCompletion(t *testing.T) {
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		t.Run(shell, func(t *testing.T) {
+			app := newCompletionApp()
+			app.Name = "test"
+			out := bytes.NewBuffer(nil)
+			if err := app.GenerateBashCompletion(out, shell); err != nil {
+				t.Fatalf("GenerateBashCompletion() error = %v", err)
+			}
+			script := out.String()
+			if !strings.Contains(script, "test __complete") && !strings.Contains(script, " __complete ") {
+				t.Errorf("script does not request completion %s", script)
+			}
+			if strings.Contains(script, "{{") || strings.Contains(script, "%!") {
+				t.Errorf("script is not rendered %s", script)
+			}
+		})
+	}
+}
+
+func TestApplication_GenerateCompletionCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	not_dir := filepath.Join(dir, "file")
+	os.WriteFile(not_dir, nil, 0644)
+
+	tests := []struct {
+		name     string
+		args     []string
+		dataHome string
+		wantFile string
+		wantOut  string
+		wantErr  string
+	}{
+		{name: "standard output", args: []string{"generate-completion", "fish"}, wantOut: "complete -c test"},
+		{name: "output file", args: []string{"generate-completion", "zsh", "--output", filepath.Join(dir, "_test")}, wantFile: filepath.Join(dir, "_test")},
+		{name: "install bash", args: []string{"generate-completion", "--install"}, wantFile: filepath.Join(dir, "data", "bash-completion", "completions", "test"), wantOut: "completion script written to"},
+		{name: "install zsh", args: []string{"generate-completion", "zsh", "--install"}, wantFile: filepath.Join(dir, "data", "zsh", "site-functions", "_test")},
+		{name: "install fish", args: []string{"generate-completion", "fish", "--install"}, wantFile: filepath.Join(dir, "config", "fish", "completions", "test.fish")},
+		{name: "install failed", args: []string{"generate-completion", "--install"}, dataHome: not_dir, wantErr: "ShellCompletionInstallFailed"},
+		{name: "output and install", args: []string{"generate-completion", "--install", "--output", filepath.Join(dir, "out")}, wantErr: "ConstraintConflicts"},
+		{name: "unknown shell", args: []string{"generate-completion", "tcsh"}, wantErr: "UnknownOneOfValue"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.dataHome != "" {
+				t.Setenv("XDG_DATA_HOME", tt.dataHome)
+			}
+			app := newCompletionApp()
+			app.Name = "test"
+			out := bytes.NewBuffer(nil)
+			app.SetWriter(out)
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(append([]string{"test"}, tt.args...))
+			if tt.wantErr != "" {
+				var int_err *i18n.Error
+				if !errors.As(err, &int_err) || int_err.GetKey() != tt.wantErr {
+					t.Fatalf("Application.Run() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Application.Run() error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+			if tt.wantFile != "" {
+				script, err := os.ReadFile(tt.wantFile)
+				if err != nil || !strings.Contains(string(script), completeCommand) {
+					t.Errorf("script %s not written: %v", tt.wantFile, err)
+				}
+			}
+		})
+	}
+}
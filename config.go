@@ -0,0 +1,139 @@
+package gocli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ez-leka/gocli/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// value read from configuration file
+type configEntry struct {
+	file  string
+	key   string
+	value interface{}
+}
+
+// loads all application config files in order; values from later files override values from earlier ones
+// files that do not exist are skipped
+func (a *Application) loadConfigFiles() error {
+	a.config = make(map[string]configEntry)
+
+	for _, file := range a.ConfigFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return i18n.NewError("ConfigFileParseFailed", SourceTemplateContext{Source: file, Extra: err.Error()})
+		}
+
+		values := make(map[string]interface{})
+		switch strings.ToLower(filepath.Ext(file)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(content, &values)
+		case ".json":
+			err = json.Unmarshal(content, &values)
+		case ".toml":
+			err = toml.Unmarshal(content, &values)
+		default:
+			return i18n.NewError("ConfigFileUnknownFormat", SourceTemplateContext{Source: file})
+		}
+		if err != nil {
+			return i18n.NewError("ConfigFileParseFailed", SourceTemplateContext{Source: file, Extra: err.Error()})
+		}
+		flattenConfig(a.config, file, "", values)
+	}
+	return nil
+}
+
+// nested sections are flattened into dot separated keys, i.e deploy.region
+// section itself is kept as well so it can set map flag
+func flattenConfig(config map[string]configEntry, file string, prefix string, values map[string]interface{}) {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if section, ok := v.(map[string]interface{}); ok {
+			flattenConfig(config, file, key, section)
+		}
+		config[key] = configEntry{file: file, key: key, value: v}
+	}
+}
+
+// config keys that can set flag, most specific first.
+// keys are scoped by command path without application name, so for "app deploy" command
+// flag --region can be set with deploy.region or region
+func configKeys(cmd *Command, name string) []string {
+	keys := make([]string, 0)
+	path := strings.Fields(cmd.FullCommand())
+	for i := len(path); i > 0; i-- {
+		keys = append(keys, strings.Join(append(append([]string{}, path[1:i]...), name), "."))
+	}
+	return keys
+}
+
+// set value of flag that was not set on command line or from environment from configuration
+func (ctx *context) setFlagFromConfig(app *Application, f IFlag) error {
+	if f.IsSetByUser() || len(app.config) == 0 {
+		return nil
+	}
+	for _, key := range configKeys(ctx.CurrentCommand, f.GetName()) {
+		entry, ok := app.config[key]
+		if !ok {
+			continue
+		}
+		values := configValueToStrings(entry.value)
+		for _, v := range values {
+			if err := f.SetValue(v); err != nil {
+				return i18n.NewError("ConfigFileValidationFailed", SourceTemplateContext{Element: f, Source: entry.file, Key: entry.key, Extra: redactValue(f, v)})
+			}
+		}
+		if len(values) > 0 {
+			f.setSource(SourceConfigFile)
+		}
+		return nil
+	}
+	return nil
+}
+
+func configValueToStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return []string{}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			values = append(values, configValueToStrings(item)...)
+		}
+		return values
+	case map[string]interface{}:
+		// map section becomes key=value entries
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, 0, len(v))
+		for _, k := range keys {
+			for _, item := range configValueToStrings(v[k]) {
+				values = append(values, k+"="+item)
+			}
+		}
+		return values
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
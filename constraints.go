@@ -0,0 +1,263 @@
+package gocli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+// RequiredIf makes flag or argument Name required when flag or argument If has value Value
+type RequiredIf struct {
+	Name  string
+	If    string
+	Value string
+}
+
+// constraints of command, flags and arguments are referred to by name.
+// Constraints of parent commands apply to all their sub-commands
+type Constraints struct {
+	Requires      map[string][]string // element requires all listed elements to be set, i.e. "tls-cert": {"tls-key"}
+	ConflictsWith map[string][]string // element cannot be set together with any of listed elements
+	AtLeastOneOf  [][]string          // at least one element of every list must be set
+	AllOrNone     [][]string          // either all elements of every list are set or none of them
+	RequiredIf    []RequiredIf
+}
+
+// finds flag or argument by name in command and its parents; flags take precedence over arguments
+func (c *Command) lookupFlagArg(name string) IFlagArg {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for _, f := range cmd.Flags {
+			if f.GetName() == name {
+				return f
+			}
+		}
+	}
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		for _, a := range cmd.Args {
+			if a.GetName() == name {
+				return a
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Command) constraintName(name string) string {
+	if fa := c.lookupFlagArg(name); fa != nil {
+		return elementName(fa)
+	}
+	return name
+}
+
+func (c *Command) constraintNames(names []string) []string {
+	display := make([]string, len(names))
+	for i, n := range names {
+		display[i] = c.constraintName(n)
+	}
+	return display
+}
+
+// unknown elements are never set
+func (c *Command) isConstraintSet(name string) bool {
+	fa := c.lookupFlagArg(name)
+	return fa != nil && fa.IsSetByUser()
+}
+
+// reports whether flag or argument is defined on sub-commands of command at any depth
+func (c *Command) subCommandsDefine(name string) bool {
+	for _, sc := range c.Commands {
+		for _, f := range sc.Flags {
+			if f.GetName() == name {
+				return true
+			}
+		}
+		for _, a := range sc.Args {
+			if a.GetName() == name {
+				return true
+			}
+		}
+		if sc.subCommandsDefine(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// every name in constraints of command and its sub-commands must be flag or argument of the command, its parents or
+// its sub-commands; misspelled name would be treated as never set
+func (c *Command) checkConstraintNames() error {
+	rules := c.Constraints
+	names := make([]string, 0)
+	for _, name := range sortedKeys(rules.Requires) {
+		names = append(append(names, name), rules.Requires[name]...)
+	}
+	for _, name := range sortedKeys(rules.ConflictsWith) {
+		names = append(append(names, name), rules.ConflictsWith[name]...)
+	}
+	for _, list := range rules.AtLeastOneOf {
+		names = append(names, list...)
+	}
+	for _, list := range rules.AllOrNone {
+		names = append(names, list...)
+	}
+	for _, r := range rules.RequiredIf {
+		names = append(names, r.Name, r.If)
+	}
+	for _, name := range names {
+		if c.lookupFlagArg(name) == nil && !c.subCommandsDefine(name) {
+			return i18n.NewError("ConstraintUnknownName", ConstraintTemplateContext{Name: c.Name, Names: []string{name}})
+		}
+	}
+
+	for _, sc := range c.Commands {
+		if err := sc.checkConstraintNames(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checks constraints of current command and its parents, returns all violated rules.
+// Rules of parents are checked on current command on purpose: names are looked up from current command up to the
+// root, so rule of parent finds flags inherited from parent as well as flags of current command
+func (ctx *context) validateConstraints() []error {
+	errs := make([]error, 0)
+	current := ctx.CurrentCommand
+	for cmd := current; cmd != nil; cmd = cmd.parent {
+		errs = append(errs, current.checkConstraints(cmd.Constraints)...)
+	}
+	return errs
+}
+
+func (c *Command) checkConstraints(rules Constraints) []error {
+	errs := make([]error, 0)
+	for _, name := range sortedKeys(rules.Requires) {
+		if !c.isConstraintSet(name) {
+			continue
+		}
+		missing := make([]string, 0)
+		for _, other := range rules.Requires[name] {
+			if !c.isConstraintSet(other) {
+				missing = append(missing, other)
+			}
+		}
+		if len(missing) > 0 {
+			errs = append(errs, i18n.NewError("ConstraintRequires", ConstraintTemplateContext{Name: c.constraintName(name), Names: c.constraintNames(missing)}))
+		}
+	}
+
+	for _, name := range sortedKeys(rules.ConflictsWith) {
+		if !c.isConstraintSet(name) {
+			continue
+		}
+		for _, other := range rules.ConflictsWith[name] {
+			if c.isConstraintSet(other) {
+				errs = append(errs, i18n.NewError("ConstraintConflicts", ConstraintTemplateContext{Name: c.constraintName(name), Names: c.constraintNames([]string{other})}))
+			}
+		}
+	}
+
+	for _, names := range rules.AtLeastOneOf {
+		set := false
+		for _, name := range names {
+			if c.isConstraintSet(name) {
+				set = true
+				break
+			}
+		}
+		if !set {
+			errs = append(errs, i18n.NewError("ConstraintAtLeastOneOf", ConstraintTemplateContext{Names: c.constraintNames(names)}))
+		}
+	}
+
+	for _, names := range rules.AllOrNone {
+		set := make([]string, 0)
+		missing := make([]string, 0)
+		for _, name := range names {
+			if c.isConstraintSet(name) {
+				set = append(set, name)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+		if len(set) > 0 && len(missing) > 0 {
+			errs = append(errs, i18n.NewError("ConstraintAllOrNone", ConstraintTemplateContext{Name: c.constraintName(set[0]), Names: c.constraintNames(missing)}))
+		}
+	}
+
+	for _, r := range rules.RequiredIf {
+		if c.isConstraintSet(r.Name) {
+			continue
+		}
+		fa := c.lookupFlagArg(r.If)
+		if fa != nil && hasValue(fa, r.Value) {
+			errs = append(errs, i18n.NewError("ConstraintRequiredIf", ConstraintTemplateContext{Name: c.constraintName(r.Name), Names: c.constraintNames([]string{r.If}), Value: r.Value}))
+		}
+	}
+	return errs
+}
+
+// rules are checked in the same order on every run
+func sortedKeys(rules map[string][]string) []string {
+	keys := make([]string, 0, len(rules))
+	for k := range rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reports whether flag or argument (or any element of cumulative one) has value; default values count too.
+// Value is parsed by the value type first so i.e. 1m matches 60s of Duration
+func hasValue(fa IFlagArg, value string) bool {
+	if fa.GetSource() == SourceNone || isMap(fa) {
+		return false
+	}
+	expected := value
+	if v, ok := newSingleValue(fa).(ISetable); ok && v.FromString(value, fa) == nil {
+		expected = fmt.Sprint(v.GetValue())
+	}
+
+	values := make([]ISetable, 0)
+	if isCumulative(fa) {
+		values = setableElements(fa)
+	} else if v, ok := fa.getDestination().(ISetable); ok {
+		values = append(values, v)
+	}
+	for _, v := range values {
+		actual := fmt.Sprint(v.GetValue())
+		if actual == expected || (fa.IsIgnoreCase() && strings.EqualFold(actual, expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// constraints of command and its parents as localized sentences for synopsis
+func (t *TemplateManager) tplConstraints(c Command) []string {
+	notes := make([]string, 0)
+	join := func(names []string) string {
+		return strings.Join(c.constraintNames(names), ", ")
+	}
+	for cmd := &c; cmd != nil; cmd = cmd.parent {
+		rules := cmd.Constraints
+		for _, name := range sortedKeys(rules.Requires) {
+			notes = append(notes, t.localizer.Sprintf("FormatConstraintRequires", c.constraintName(name), join(rules.Requires[name])))
+		}
+		for _, name := range sortedKeys(rules.ConflictsWith) {
+			notes = append(notes, t.localizer.Sprintf("FormatConstraintConflicts", c.constraintName(name), join(rules.ConflictsWith[name])))
+		}
+		for _, names := range rules.AtLeastOneOf {
+			notes = append(notes, t.localizer.Sprintf("FormatConstraintAtLeastOneOf", join(names)))
+		}
+		for _, names := range rules.AllOrNone {
+			notes = append(notes, t.localizer.Sprintf("FormatConstraintAllOrNone", join(names)))
+		}
+		for _, r := range rules.RequiredIf {
+			notes = append(notes, t.localizer.Sprintf("FormatConstraintRequiredIf", c.constraintName(r.Name), c.constraintName(r.If), r.Value))
+		}
+	}
+	return notes
+}
@@ -0,0 +1,64 @@
+package gocli
+
+import (
+	"reflect"
+	"strings"
+)
+
+// EnumValue is one of allowed values of enumerated flag or argument
+type EnumValue[T any] struct {
+	Name        string // value on command line
+	Value       T      // value returned by GetValue
+	Description string // shown in help and shell completion
+}
+
+// Enum is a list of allowed values of flag or argument. Flag or argument with Enum accepts only names of its values
+// and GetValue returns Go value of matched entry instead of the name, i.e.
+//
+//	&Flag[OneOf]{
+//		Name: "level",
+//		Enum: Enum[log.Level]{
+//			{Name: "debug", Value: log.DebugLevel, Description: "verbose output"},
+//			{Name: "info", Value: log.InfoLevel, Description: "normal output"},
+//		},
+//	}
+type Enum[T any] []EnumValue[T]
+
+// IEnum is implemented by Enum of any value type
+type IEnum interface {
+	GetNames() []string
+	GetDescription(name string) string
+	// returns name as defined in enum and Go value of entry matching the name
+	Lookup(name string, ignore_case bool) (string, interface{}, bool)
+	GetValueType() reflect.Type
+}
+
+func (e Enum[T]) GetNames() []string {
+	names := make([]string, 0, len(e))
+	for _, v := range e {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+func (e Enum[T]) GetDescription(name string) string {
+	for _, v := range e {
+		if v.Name == name {
+			return v.Description
+		}
+	}
+	return ""
+}
+
+func (e Enum[T]) Lookup(name string, ignore_case bool) (string, interface{}, bool) {
+	for _, v := range e {
+		if v.Name == name || (ignore_case && strings.EqualFold(v.Name, name)) {
+			return v.Name, v.Value, true
+		}
+	}
+	return "", nil, false
+}
+
+func (e Enum[T]) GetValueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,110 @@
+package gocli
+
+import (
+	"errors"
+	"strings"
+)
+
+// exit statuses used by RunAndExit
+const (
+	ExitOK    = 0
+	ExitError = 1 // action failed
+	ExitUsage = 2 // command line could not be parsed or validated
+)
+
+// ExitCoder is implemented by errors that know exit status application should terminate with
+type ExitCoder interface {
+	ExitCode() int
+}
+
+type helpRequestedError struct{}
+
+func (e *helpRequestedError) Error() string {
+	return "help requested"
+}
+
+func (e *helpRequestedError) ExitCode() int {
+	return ExitOK
+}
+
+// ErrHelpRequested is returned by Run after usage was printed because of help flag or help command
+var ErrHelpRequested error = &helpRequestedError{}
+
+// UsageError is returned by Run when command line cannot be parsed
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UsageError) ExitCode() int {
+	return ExitUsage
+}
+
+// ValidationError is returned by Run when parsed flags, arguments or commands fail validation
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ValidationError) ExitCode() int {
+	return ExitUsage
+}
+
+// ValidationErrors lists all validation errors found when Application.ReportAllErrors is set.
+// Errors of gocli are *i18n.Error with key of the error and flag, argument or command as data
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is reports whether any of the errors matches target, so errors.Is looks into the list
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target, so errors.As looks into the list
+func (e ValidationErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExitCode returns exit status for error returned by Run:
+// ExitOK for nil or ErrHelpRequested, status of ExitCoder if error implements it and ExitError otherwise
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return ExitError
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"log"
 	"os"
 
 	"github.com/ez-leka/gocli"
@@ -49,10 +48,6 @@ func main() {
 		},
 	},
 	)
-	err := app.Run(os.Args)
-	if err != nil {
-		msg := err.Error()
-		log.Fatalf("%s, try --help", msg)
-	}
+	app.RunAndExit(os.Args)
 
 }
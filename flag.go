@@ -1,16 +1,33 @@
 package gocli
 
-import "strings"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// prefix of generated negative form of boolean flag, i.e. --no-color
+const negatedFlagPrefix = "no-"
+
+// default placeholder of map flags and arguments
+const mapPlaceholder = "KEY=VALUE"
+
+// value set for every occurrence of counter flag on command line
+const counterIncrement = "+1"
 
 type FlagValidator func(a *Application, f IFlag) error
 
+// TFlag is value type of flag: TArgFlag types, Bool or Counter
 type TFlag interface {
-	TArgFlag | Bool
+	TArgFlag | Bool | Counter
 }
 
 type IFlag interface {
 	IFlagArg
 	IsBool() bool
+	IsNegatable() bool
+	IsCounter() bool
 	SetShort(c rune)
 	GetShort() rune
 	SetLevel(int)
@@ -29,11 +46,26 @@ type Flag[T TFlag] struct {
 	Placeholder      string
 	ValidationGroups []string
 	Validator        FlagValidator
-	Hidden           bool // can be used on command line but will not show on help
+	Hidden           bool           // can be used on command line but will not show on help
+	EnvVars          []string       // environment variables to take value from if flag is not set on command line; first one set wins
+	Min              string         // minimal value of numeric flag, parsed as flag value
+	Max              string         // maximal value of numeric flag, parsed as flag value
+	Enum             IEnum          // allowed values of flag, GetValue returns Go value of matched entry
+	IgnoreCase       bool           // match hints and enum values ignoring case
+	PathMode         PathMode       // requirements for value of File, Dir and Path flag
+	Extensions       []string       // allowed extensions of File, Dir and Path flag, i.e. .yaml
+	Layouts          []string       // layouts of TimeStamp flag used instead of default ones
+	Location         *time.Location // time zone of TimeStamp flag value without zone, UTC if not set
+	DisableNegation  bool           // do not accept --no-<name> for boolean flag
+	Secret           bool           // value is not echoed when prompted for
+	Completer        CompletionFunc // offers shell completion candidates for value of flag instead of hints
 	// for internal use
-	isSetByUser bool
-	level       int
-	internal    bool
+	isSetByUser    bool
+	source         ValueSource
+	level          int
+	internal       bool
+	ownDestination bool          // destination was created by gocli, not provided by user
+	structField    reflect.Value // struct field bound by FromStruct, value is copied to it when set
 }
 
 func (f *Flag[T]) GetType() string {
@@ -63,6 +95,16 @@ func (f *Flag[T]) IsBool() bool {
 	return ok
 }
 
+func (f *Flag[T]) IsCounter() bool {
+	_, ok := any(f.Destination).(*Counter)
+	return ok
+}
+
+// boolean flags can be set to false with --no-<name> unless negation is disabled
+func (f *Flag[T]) IsNegatable() bool {
+	return f.IsBool() && !f.DisableNegation && !f.internal
+}
+
 func (f *Flag[T]) GetName() string {
 	return f.Name
 }
@@ -77,11 +119,63 @@ func (f *Flag[T]) GetDefault() string {
 	return f.Default
 }
 func (f *Flag[T]) GetHints() []string {
+	if len(f.Hints) == 0 && f.Enum != nil {
+		return f.Enum.GetNames()
+	}
 	return f.Hints
 }
+func (f *Flag[T]) GetEnum() IEnum {
+	return f.Enum
+}
+func (f *Flag[T]) IsIgnoreCase() bool {
+	return f.IgnoreCase
+}
+func (f *Flag[T]) IsSecret() bool {
+	return f.Secret
+}
+
+// String returns flag with its value for logging and debugging; value of secret flag is redacted
+func (f *Flag[T]) String() string {
+	if f.Destination == nil || f.source == SourceNone {
+		return "--" + f.Name
+	}
+	return "--" + f.Name + "=" + redactValue(f, fmt.Sprint(f.GetValue()))
+}
+func (f *Flag[T]) GetPathMode() PathMode {
+	return f.PathMode
+}
+func (f *Flag[T]) GetExtensions() []string {
+	return f.Extensions
+}
+func (f *Flag[T]) GetLayouts() []string {
+	return f.Layouts
+}
+func (f *Flag[T]) GetLocation() *time.Location {
+	return f.Location
+}
+func (f *Flag[T]) GetEnvVars() []string {
+	return f.EnvVars
+}
+func (f *Flag[T]) GetCompleter() CompletionFunc {
+	return f.Completer
+}
+func (f *Flag[T]) GetMin() string {
+	return f.Min
+}
+func (f *Flag[T]) GetMax() string {
+	return f.Max
+}
+func (f *Flag[T]) GetSource() ValueSource {
+	return f.source
+}
+func (f *Flag[T]) setSource(source ValueSource) {
+	f.source = source
+}
 func (f *Flag[T]) GetPlaceholder() string {
 	if f.Placeholder != "" {
 		return f.Placeholder
+	} else if isMap(f) {
+		return mapPlaceholder
 	} else {
 		return f.Name
 	}
@@ -118,6 +212,7 @@ func (f *Flag[T]) SetByUser() {
 func (f *Flag[T]) getDestination() interface{} {
 	if f.Destination == nil {
 		f.Destination = new(T)
+		f.ownDestination = true
 	}
 	return f.Destination
 }
@@ -139,18 +234,37 @@ func (f *Flag[T]) SetValue(value string) error {
 	}
 
 	f.SetByUser()
+	f.source = SourceCommandLine
 	return nil
 
 }
 
 func (f *Flag[T]) Clear() {
 	f.isSetByUser = false
-	f.Destination = new(T)
+	f.source = SourceNone
+	// destination provided by user is left alone, struct field bound by FromStruct is reset
+	if f.Destination == nil || f.ownDestination {
+		f.Destination = new(T)
+		f.ownDestination = true
+	}
+	if f.structField.IsValid() {
+		f.structField.Set(reflect.Zero(f.structField.Type()))
+	}
+}
+
+func (f *Flag[T]) copyToField() {
+	if f.structField.IsValid() {
+		copyToField(f.structField, reflect.ValueOf(f.getDestination()).Elem())
+	}
 }
 
 func (f *Flag[T]) ValidateWrapper(a *Application) error {
+	// range is checked before custom validator, value of secret flag is hidden in errors of both
+	if err := checkRange(f); err != nil {
+		return redactError(f, err)
+	}
 	if f.Validator != nil {
-		return f.Validator(a, f)
+		return redactError(f, f.Validator(a, f))
 	}
 	return nil
 }
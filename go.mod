@@ -3,8 +3,10 @@ module github.com/ez-leka/gocli
 go 1.19
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/mitchellh/go-wordwrap v1.0.1
 	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -20,6 +22,6 @@ require (
 	github.com/olekukonko/ts v0.0.0-20171002115256-78ecb04241c0
 	github.com/russross/blackfriday/v2 v2.1.0
 	github.com/sirupsen/logrus v1.9.3
-	golang.org/x/sys v0.8.0 // indirect
+	golang.org/x/sys v0.8.0
 	golang.org/x/text v0.9.0
 )
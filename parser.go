@@ -1,6 +1,10 @@
 package gocli
 
 import (
+	gocontext "context"
+	"errors"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/ez-leka/gocli/i18n"
@@ -11,6 +15,11 @@ import (
 type context struct {
 	CurrentCommand   *Command
 	mixArgsAndFlags  bool
+	suggestDistance  int
+	allowPrefixMatch bool
+	expandAtFiles    bool
+	reportAllErrors  bool
+	prompter         IPrompter // asks for missing required flags and arguments if set
 	argsOnly         bool
 	noCommands       bool
 	cli_args         []string
@@ -18,6 +27,7 @@ type context struct {
 	flags_lookup     map[string]IFlag
 	arguments_lookup []IArg // arguments are positioned so array , not a map
 	level            int    // depth of sub-command chain
+	completing       bool   // set around parse of completion request, values must not have side effects
 }
 
 func (ctx *context) nextArg() IArg {
@@ -144,13 +154,22 @@ func (ctx *context) parse(app *Application, args []string) error {
 	// initiaze context
 	// the very first command is app itself
 	ctx.mixArgsAndFlags = app.MixArgsAndFlags
+	ctx.suggestDistance = app.SuggestionDistance
+	// completion works on partial words, so prefixes are never matched for it
+	ctx.allowPrefixMatch = app.AllowPrefixMatching && !ctx.completing
+	ctx.expandAtFiles = app.ExpandAtFiles && !ctx.completing
+	ctx.reportAllErrors = app.ReportAllErrors
+	ctx.prompter = app.getPrompter()
 	ctx.cli_args = args
 	ctx.CurrentCommand = &app.Command
 	err = ctx.mergeFlags(app.Flags)
 	if err != nil {
 		return err
 	}
-	ctx.mergeArgs(app.Args)
+	err = ctx.mergeArgs(app.Args)
+	if err != nil {
+		return err
+	}
 	ctx.updateCommandValidatables()
 
 	for token, ok := ctx.popCliArg(); ok; token, ok = ctx.popCliArg() {
@@ -186,106 +205,87 @@ func (ctx *context) parse(app *Application, args []string) error {
 
 	}
 
-	// Set defaults for all flags that are not set by user and have a default value
-	// Note: using internal function so SetByUser is not set
-	for _, f := range ctx.flags_lookup {
-		if !f.IsSetByUser() && f.GetDefault() != "" {
-			setFlagArgValue(f, f.GetDefault())
-		}
-	}
-	// Set defaults for all remaining arguments
-	for arg := ctx.nextArg(); arg != nil; arg = ctx.nextArg() {
-		if !arg.IsSetByUser() && arg.GetDefault() != "" {
-			setFlagArgValue(arg, arg.GetDefault())
-		}
+	// completion needs only what is on command line, secret files, environment and configuration are not read
+	if ctx.completing {
+		ctx.setDefaults()
+		return nil
 	}
 
-	return nil
-}
-
-func (ctx *context) resolveCompletion(app *Application, args []string) []string {
-
-	completions := make([]string, 0)
-
-	var (
-		currArg string
-		prevArg string
-	)
-
-	num_args := len(args)
-	if num_args > 1 {
-		currArg = args[len(args)-1]
-	}
-	if num_args > 2 {
-		prevArg = args[len(args)-2]
+	if err = ctx.readSecretFiles(); err != nil {
+		return err
 	}
 
-	// check if flag started - skip if special completion flag
-	if currArg != "" && strings.HasPrefix(currArg, "--") {
-		if ctx.argsOnly {
-			return nil
+	// Set values from environment for all flags and arguments that are not set on command line
+	for _, f := range ctx.flags_lookup {
+		if err = setFlagArgFromEnv(f); err != nil {
+			return err
 		}
-		flag_pref := currArg[2:]
-		for _, flag := range ctx.CurrentCommand.Flags {
-			if !flag.IsSetByUser() || flag.IsCumulative() {
-				// if fully matched do options if any
-				if flag.GetName() == flag_pref {
-					completions = append(completions, flag.GetHints()...)
-					return completions
-				}
-				// check if partially match - still loking for a flag name
-				if strings.HasPrefix(flag.GetName(), flag_pref) {
-					completions = append(completions, "--"+flag.GetName())
-				}
-			}
+	}
+	for _, a := range ctx.arguments_lookup {
+		if err = setFlagArgFromEnv(a); err != nil {
+			return err
 		}
-		return completions
 	}
-
-	// prev argument was a flag and we are trying to get complition for possible values (if has hints)
-	prev_name := prevArg[2:]
-	if strings.HasPrefix(prevArg, "--") && prev_name != app.bashCompletionFlag.GetName() {
-		if flag, ok := ctx.flags_lookup[prev_name]; ok {
-			for _, hint := range flag.GetHints() {
-				if strings.HasPrefix(hint, currArg) {
-					completions = append(completions, hint)
-				}
-			}
-			return completions
+	// Set values from configuration files for all flags that are still not set
+	for _, f := range ctx.flags_lookup {
+		if err = ctx.setFlagFromConfig(app, f); err != nil {
+			return err
 		}
 	}
 
-	for _, subc := range ctx.CurrentCommand.Commands {
-		completions = append(completions, subc.Name)
-	}
+	ctx.setDefaults()
+	return nil
+}
 
-	for _, arg := range ctx.CurrentCommand.Args {
-		if !arg.IsSetByUser() || arg.IsCumulative() {
-			for _, hint := range arg.GetHints() {
-				if strings.HasPrefix(hint, currArg) {
-					completions = append(completions, hint)
-				}
-			}
+// Set defaults for all flags and remaining arguments that are not set by user and have a default value
+// Note: using internal function so SetByUser is not set
+func (ctx *context) setDefaults() {
+	for _, f := range ctx.flags_lookup {
+		if !f.IsSetByUser() && f.GetDefault() != "" && !(ctx.completing && isPathValue(f)) {
+			setFlagArgValue(f, f.GetDefault())
+			f.setSource(SourceDefault)
 		}
 	}
-
-	for _, flag := range ctx.CurrentCommand.Flags {
-		if !flag.IsSetByUser() || flag.IsCumulative() {
-			completions = append(completions, "--"+flag.GetName())
+	for arg := ctx.nextArg(); arg != nil; arg = ctx.nextArg() {
+		if !arg.IsSetByUser() && arg.GetDefault() != "" && !(ctx.completing && isPathValue(arg)) {
+			setFlagArgValue(arg, arg.GetDefault())
+			arg.setSource(SourceDefault)
 		}
 	}
-	return completions
+}
+
+// sets value from command line; while completing values of files and directories are not checked on file system,
+// element is only marked as set
+func (ctx *context) setValue(fa IFlagArg, value string) error {
+	if ctx.completing && isPathValue(fa) {
+		fa.SetByUser()
+		fa.setSource(SourceCommandLine)
+		return nil
+	}
+	return fa.SetValue(value)
 }
 
 func (ctx *context) processArg(token string) error {
 
-	if cmd, ok := ctx.CurrentCommand.commands_map[token]; ok && !ctx.noCommands {
+	cmd, ok := ctx.CurrentCommand.commands_map[token]
+	// token can be a prefix of a command only if it cannot be an argument
+	if !ok && ctx.allowPrefixMatch && !ctx.noCommands && ctx.arg_pos >= len(ctx.arguments_lookup) {
+		var err error
+		if cmd, err = ctx.matchCommandPrefix(token); err != nil {
+			return err
+		}
+		ok = cmd != nil
+	}
+	if ok && !ctx.noCommands {
 		// this is command
 
 		ctx.CurrentCommand = cmd
 		ctx.level++
-		ctx.mergeArgs(cmd.Args)
-		err := ctx.mergeFlags(cmd.Flags)
+		err := ctx.mergeArgs(cmd.Args)
+		if err != nil {
+			return err
+		}
+		err = ctx.mergeFlags(cmd.Flags)
 		ctx.updateCommandValidatables()
 		if err != nil {
 			return err
@@ -305,14 +305,14 @@ func (ctx *context) processArg(token string) error {
 		if arg == nil {
 			return i18n.NewError("ExtraArgument", TokenTemplateContext{Extra: token})
 		}
-		err := arg.SetValue(token)
+		err := ctx.setValue(arg, token)
 		if err != nil {
 			return err
 		}
 		return nil
 	}
 
-	return i18n.NewError("UnexpectedTokenTemplate", TokenTemplateContext{Name: token, Extra: "command"})
+	return i18n.NewError("UnexpectedTokenTemplate", TokenTemplateContext{Name: token, Extra: "command", Suggestions: ctx.suggestCommands(token)})
 
 }
 
@@ -320,8 +320,9 @@ func (ctx *context) processLongFlag(flag_token string) error {
 
 	flag_token = flag_token[2:]
 
-	// long flag can be of 3 types
+	// long flag can be of 4 types
 	// --flag - it is a bool flag
+	// --no-flag - negated bool flag
 	// --flag=value
 	// --flag value
 	// first isolate actual flag name
@@ -329,15 +330,47 @@ func (ctx *context) processLongFlag(flag_token string) error {
 	flag_name := flag_parts[0]
 
 	// find flag
+	negated := false
 	flag, ok := ctx.flags_lookup[flag_name]
+	if !ok && strings.HasPrefix(flag_name, negatedFlagPrefix) {
+		// could be --no-<name> of negatable bool flag
+		if f, found := ctx.flags_lookup[strings.TrimPrefix(flag_name, negatedFlagPrefix)]; found && f.IsNegatable() {
+			flag, ok, negated = f, true, true
+		}
+	}
+	if !ok && ctx.allowPrefixMatch {
+		var err error
+		if flag, err = ctx.matchLongFlagPrefix(flag_name); err != nil {
+			return err
+		}
+		ok = flag != nil
+	}
 	if !ok {
-		return i18n.NewError("UnknownElementTemplate", ElementTemplateContext{Element: &Flag[String]{Name: flag_name}})
+		return i18n.NewError("UnknownElementTemplate", ElementTemplateContext{Element: &Flag[String]{Name: flag_name}, Suggestions: ctx.suggestLongFlags(flag_name)})
 	}
 
 	// figure out flag value
 	var flag_value string
 	if flag.IsBool() {
-		flag_value = "true"
+		switch {
+		case negated && len(flag_parts) == 2:
+			// negated flag cannot have a value
+			return i18n.NewError("FlagValidationFailed", ElementTemplateContext{Element: flag, Extra: redactValue(flag, flag_parts[1])})
+		case negated:
+			flag_value = "false"
+		case len(flag_parts) == 2:
+			// explicit value, i.e. --flag=false
+			flag_value = flag_parts[1]
+		default:
+			flag_value = "true"
+		}
+	} else if flag.IsCounter() {
+		if len(flag_parts) == 2 {
+			// explicit count, i.e. --verbose=3
+			flag_value = flag_parts[1]
+		} else {
+			flag_value = counterIncrement
+		}
 	} else {
 		if len(flag_parts) == 2 {
 			// value was assigned via =
@@ -346,18 +379,47 @@ func (ctx *context) processLongFlag(flag_token string) error {
 			// flag value must be next cli argument
 			flag_value, ok = ctx.popCliArg()
 			if !ok {
-				i18n.NewError("UnexpectedFlagValueTemplate", ElementTemplateContext{Element: flag, Extra: flag_value})
+				return i18n.NewError("UnexpectedFlagValueTemplate", ElementTemplateContext{Element: flag, Extra: redactValue(flag, flag_value)})
 			}
 		}
+		var err error
+		if flag_value, err = ctx.expandAtFile(flag, flag_value); err != nil {
+			return err
+		}
 	}
-	err := flag.SetValue(flag_value)
+	err := ctx.setValue(flag, flag_value)
 	if err != nil {
-		return i18n.NewError("FlagValidationFailed", ElementTemplateContext{Element: flag, Extra: flag_value})
+		return flagValueError(flag, flag_value, err)
 	}
 
 	return nil
 }
 
+// error of setting flag value; errors of value types, i.e. invalid format or path, already name the flag and are
+// returned as is, other errors are reported as invalid flag value
+func flagValueError(flag IFlag, value string, err error) error {
+	var int_err *i18n.Error
+	if errors.As(err, &int_err) {
+		return err
+	}
+	return i18n.NewError("FlagValidationFailed", ElementTemplateContext{Element: flag, Extra: redactValue(flag, value)})
+}
+
+// replaces @path flag value with content of the file without trailing new line, @@value is literal @value
+func (ctx *context) expandAtFile(flag IFlag, value string) (string, error) {
+	if !ctx.expandAtFiles || !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	if strings.HasPrefix(value, "@@") {
+		return value[1:], nil
+	}
+	content, err := os.ReadFile(value[1:])
+	if err != nil {
+		return "", i18n.NewError("AtFileReadFailed", ElementTemplateContext{Element: flag, Extra: value[1:]})
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(content), "\n"), "\r"), nil
+}
+
 func (ctx *context) processShortFlag(flag_token string) error {
 
 	flag_token = flag_token[1:]
@@ -372,6 +434,10 @@ func (ctx *context) processShortFlag(flag_token string) error {
 			if flag.IsBool() {
 				flag.SetValue("true")
 				continue
+			} else if flag.IsCounter() {
+				// every occurrence of counter increments it, i.e. -vvv
+				flag.SetValue(counterIncrement)
+				continue
 			} else {
 				//we have non-boolean flag se we need a value
 				var flag_value string
@@ -383,12 +449,16 @@ func (ctx *context) processShortFlag(flag_token string) error {
 					// next argument is a flag value
 					flag_value, ok = ctx.popCliArg()
 					if !ok {
-						return i18n.NewError("UnexpectedFlagValueTemplate", ElementTemplateContext{Element: flag, Extra: flag_value})
+						return i18n.NewError("UnexpectedFlagValueTemplate", ElementTemplateContext{Element: flag, Extra: redactValue(flag, flag_value)})
 					}
 				}
-				err := flag.SetValue(flag_value)
+				flag_value, err := ctx.expandAtFile(flag, flag_value)
 				if err != nil {
-					return i18n.NewError("FlagValidationFailed", ElementTemplateContext{Element: flag, Extra: flag_value})
+					return err
+				}
+				err = ctx.setValue(flag, flag_value)
+				if err != nil {
+					return flagValueError(flag, flag_value, err)
 				}
 				return nil
 			}
@@ -400,6 +470,76 @@ func (ctx *context) processShortFlag(flag_token string) error {
 	return nil
 }
 
+// sub-command whose name or alias is the only one starting with prefix. Returns nil if nothing matched
+func (ctx *context) matchCommandPrefix(prefix string) (*Command, error) {
+	matched := make(map[*Command]bool)
+	candidates := make([]string, 0)
+	for name, cmd := range ctx.CurrentCommand.commands_map {
+		if cmd.IsHidden() || !strings.HasPrefix(name, prefix) || matched[cmd] {
+			continue
+		}
+		matched[cmd] = true
+		candidates = append(candidates, cmd.Name)
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return ctx.CurrentCommand.commands_map[candidates[0]], nil
+	default:
+		sort.Strings(candidates)
+		return nil, i18n.NewError("AmbiguousPrefixTemplate", TokenTemplateContext{Name: prefix, Extra: "command", Suggestions: candidates})
+	}
+}
+
+// long flag that is the only one starting with prefix. Returns nil if nothing matched
+func (ctx *context) matchLongFlagPrefix(prefix string) (IFlag, error) {
+	var match IFlag
+	candidates := make([]string, 0)
+	for name, f := range ctx.flags_lookup {
+		if len(name) == 1 || f.IsInternal() || f.IsHidden() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		// file flag of secret flag is matched only by prefix longer than secret flag name, i.e. --token-f
+		if sf, ok := f.(*secretFileFlag); ok && strings.HasPrefix(sf.secret.GetName(), prefix) {
+			continue
+		}
+		match = f
+		candidates = append(candidates, "--"+name)
+	}
+	if len(candidates) > 1 {
+		sort.Strings(candidates)
+		return nil, i18n.NewError("AmbiguousPrefixTemplate", TokenTemplateContext{Name: "--" + prefix, Extra: "flag", Suggestions: candidates})
+	}
+	return match, nil
+}
+
+// sub-commands of current command (including aliases) similar to unknown token
+func (ctx *context) suggestCommands(token string) []string {
+	candidates := make([]string, 0, len(ctx.CurrentCommand.commands_map))
+	for name, cmd := range ctx.CurrentCommand.commands_map {
+		if !cmd.IsHidden() {
+			candidates = append(candidates, name)
+		}
+	}
+	return suggest(token, candidates, ctx.suggestDistance)
+}
+
+// long flags similar to unknown flag name
+func (ctx *context) suggestLongFlags(name string) []string {
+	candidates := make([]string, 0, len(ctx.flags_lookup))
+	for fname, f := range ctx.flags_lookup {
+		if len(fname) > 1 && !f.IsHidden() && !f.IsInternal() {
+			candidates = append(candidates, fname)
+		}
+	}
+	suggestions := suggest(name, candidates, ctx.suggestDistance)
+	for i := range suggestions {
+		suggestions[i] = "--" + suggestions[i]
+	}
+	return suggestions
+}
+
 // Group flags and arguments according to their validation group; ignore short flags
 //
 // only flags and arguments from one group can be set,  i.e groups are mutially exclusive
@@ -450,6 +590,39 @@ func (ctx *context) validateGrouping(set map[string]IValidatable) ([]IValidatabl
 	return validate_set, nil
 
 }
+
+// elements that do not depend on validation group: ungrouped ones and ones in every group of current command
+func (ctx *context) groupIndependent() map[IValidatable]bool {
+	grouped := ctx.CurrentCommand.GetGroupedFlagsAndArgs()
+
+	independent := make(map[IValidatable]bool)
+	for _, v := range mergeValidatables(grouped.Ungrouped) {
+		independent[v] = true
+	}
+	in_groups := make(map[IValidatable]int)
+	groups := 0
+	for _, g := range grouped.Groups {
+		if g.Command != "" && g.Command != ctx.CurrentCommand.Name {
+			continue
+		}
+		groups++
+		// element can be listed in group more than once, i.e. as flag and argument lists
+		seen := make(map[IValidatable]bool)
+		for _, v := range mergeValidatables(g) {
+			if !seen[v] {
+				seen[v] = true
+				in_groups[v]++
+			}
+		}
+	}
+	for v, n := range in_groups {
+		if n == groups {
+			independent[v] = true
+		}
+	}
+	return independent
+}
+
 func (ctx *context) updateCommandValidatables() {
 
 	ctx.CurrentCommand.setByUser = true
@@ -477,16 +650,35 @@ func (ctx *context) updateCommandValidatables() {
 
 func (ctx *context) validate(app *Application) error {
 
-	var err error
+	// validation stops on first error unless all errors are reported
+	errs := make(ValidationErrors, 0)
+	report := func(err error) bool {
+		errs = append(errs, err)
+		return !ctx.reportAllErrors
+	}
 
 	// we also show help if last parsed command was not the leaf of the command chain
 	if !ctx.CurrentCommand.isLeaf() {
 		return i18n.NewError("CommandRequired", ctx.CurrentCommand)
 	}
 
+	// elements checked for required, nil means all of validation group
+	var check_required map[IValidatable]bool
 	validation_group, err := ctx.validateGrouping(ctx.CurrentCommand.validatables)
 	if err != nil {
-		return err
+		if report(err) {
+			return err
+		}
+		// group is unknown - validate all elements but check required only for those not depending on group
+		check_required = ctx.groupIndependent()
+		names := make([]string, 0, len(ctx.CurrentCommand.validatables))
+		for name := range ctx.CurrentCommand.validatables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			validation_group = append(validation_group, ctx.CurrentCommand.validatables[name])
+		}
 	}
 
 	// call validators for flags and arguments
@@ -495,8 +687,7 @@ func (ctx *context) validate(app *Application) error {
 			// command is validated last
 			continue
 		}
-		err = vo.ValidateWrapper(app)
-		if err != nil {
+		if err = vo.ValidateWrapper(app); err != nil && report(err) {
 			return err
 		}
 	}
@@ -513,35 +704,82 @@ func (ctx *context) validate(app *Application) error {
 			args = append(args, arg)
 		}
 	}
+	// arguments in order of their position, flags by name, so missing ones are reported and prompted for in stable order
+	position := make(map[IArg]int, len(ctx.arguments_lookup))
+	for i, a := range ctx.arguments_lookup {
+		position[a] = i
+	}
+	slices.SortStableFunc(args, func(a, b IArg) bool {
+		return position[a] < position[b]
+	})
+	slices.SortStableFunc(flags, func(a, b IFlag) bool {
+		return a.GetName() < b.GetName()
+	})
+
+	// missing element is prompted for if possible
+	missing := func(fa IFlagArg, key string) error {
+		if ctx.prompter != nil {
+			if err := ctx.promptFor(app, fa); err != nil {
+				return err
+			}
+			if fa.IsSetByUser() {
+				return nil
+			}
+		}
+		return i18n.NewError(key, fa)
+	}
 	for _, vo := range args {
+		if check_required != nil && !check_required[vo] {
+			continue
+		}
 		if vo.IsRequired() && !vo.IsSetByUser() {
-			return i18n.NewError("MissingRequiredArg", vo)
+			if err = missing(vo, "MissingRequiredArg"); err != nil && report(err) {
+				return err
+			}
 		}
 	}
 	for _, vo := range flags {
+		if check_required != nil && !check_required[vo] {
+			continue
+		}
 		if vo.IsRequired() && !vo.IsSetByUser() {
-			return i18n.NewError("MissingRequiredFlag", vo)
+			if err = missing(vo, "MissingRequiredFlag"); err != nil && report(err) {
+				return err
+			}
+		}
+	}
+
+	for _, err = range ctx.validateConstraints() {
+		if report(err) {
+			return err
 		}
 	}
 
 	// finally call validator for commands
 	cmd := ctx.CurrentCommand
 	for cmd != nil {
-		err := cmd.ValidateWrapper(app)
-		if err != nil {
+		if err = cmd.ValidateWrapper(app); err != nil && report(err) {
 			return err
 		}
 		cmd = cmd.parent
 	}
+
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-func (ctx *context) execute(app *Application) error {
+func (ctx *context) execute(run_ctx gocontext.Context, app *Application) error {
 	var data interface{} = nil
 	var err error
 	cmd := ctx.CurrentCommand
 	for cmd != nil {
-		data, err = cmd.ActionWrapper(app, data)
+		// do not start next action if run was cancelled
+		if err = run_ctx.Err(); err != nil {
+			return err
+		}
+		data, err = cmd.runAction(run_ctx, app, data)
 		if err != nil {
 			return err
 		}
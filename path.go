@@ -0,0 +1,248 @@
+package gocli
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+type File String // existing path to a file or directory
+type Dir String  // existing path to a directory
+type Path String // path without implicit requirements, use PathMode to set them
+
+// PathMode lists requirements for value of File, Dir and Path flags and arguments
+type PathMode int
+
+const (
+	PathExists    PathMode = 1 << iota // path must exist
+	PathNotExists                      // path must not exist, i.e. output file that should not be overwritten
+	PathReadable                       // path must be readable
+	PathWritable                       // path must be writable; for not existing path its directory must be writable
+	PathDir                            // path must be a directory
+	PathRegular                        // path must be a regular file
+)
+
+// path types expand glob pattern of cumulative flag or argument into separate values
+type expandable interface {
+	expand(pattern string, fa IFlagArg) ([]string, error)
+}
+
+func (s *File) GetReturnType() reflect.Type {
+	return reflect.TypeOf("")
+}
+
+func (s *File) FromString(v string, fa IFlagArg) error {
+	path, err := parsePath(v, fa, PathExists)
+	if err != nil {
+		return err
+	}
+	*s = File(path)
+	return nil
+}
+
+func (s *File) GetValue() interface{} {
+	return string(*s)
+}
+
+func (s *File) Complete(prefix string, fa IFlagArg) []Completion {
+	return completePaths(prefix, fa, false)
+}
+
+func (s *File) shellCompletion(fa IFlagArg) ([]Completion, CompletionDirective) {
+	return extensionCompletions(fa), CompletionFiles
+}
+
+func (s *File) expand(pattern string, fa IFlagArg) ([]string, error) {
+	return expandPaths(pattern, fa, PathExists)
+}
+
+func (s *Dir) GetReturnType() reflect.Type {
+	return reflect.TypeOf("")
+}
+
+func (s *Dir) FromString(v string, fa IFlagArg) error {
+	path, err := parsePath(v, fa, PathExists|PathDir)
+	if err != nil {
+		return err
+	}
+	*s = Dir(path)
+	return nil
+}
+
+func (s *Dir) GetValue() interface{} {
+	return string(*s)
+}
+
+func (s *Dir) Complete(prefix string, fa IFlagArg) []Completion {
+	return completePaths(prefix, fa, true)
+}
+
+func (s *Dir) shellCompletion(fa IFlagArg) ([]Completion, CompletionDirective) {
+	return nil, CompletionDirs
+}
+
+func (s *Dir) expand(pattern string, fa IFlagArg) ([]string, error) {
+	return expandPaths(pattern, fa, PathExists|PathDir)
+}
+
+func (s *Path) GetReturnType() reflect.Type {
+	return reflect.TypeOf("")
+}
+
+func (s *Path) FromString(v string, fa IFlagArg) error {
+	path, err := parsePath(v, fa, 0)
+	if err != nil {
+		return err
+	}
+	*s = Path(path)
+	return nil
+}
+
+func (s *Path) GetValue() interface{} {
+	return string(*s)
+}
+
+func (s *Path) Complete(prefix string, fa IFlagArg) []Completion {
+	return completePaths(prefix, fa, fa.GetPathMode()&PathDir != 0)
+}
+
+func (s *Path) shellCompletion(fa IFlagArg) ([]Completion, CompletionDirective) {
+	if fa.GetPathMode()&PathDir != 0 {
+		return nil, CompletionDirs
+	}
+	return extensionCompletions(fa), CompletionFiles
+}
+
+func (s *Path) expand(pattern string, fa IFlagArg) ([]string, error) {
+	return expandPaths(pattern, fa, 0)
+}
+
+// glob pattern of single value must match exactly one path
+func parsePath(v string, fa IFlagArg, mode PathMode) (string, error) {
+	paths, err := expandPaths(v, fa, mode)
+	if err != nil {
+		return "", err
+	}
+	if len(paths) > 1 {
+		return "", i18n.NewError("PathMultipleMatches", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	return paths[0], nil
+}
+
+// expands glob pattern and checks every match; pattern without matches is checked as is
+func expandPaths(pattern string, fa IFlagArg, mode PathMode) ([]string, error) {
+	mode = effectivePathMode(mode, fa.GetPathMode())
+
+	paths := []string{pattern}
+	if strings.ContainsAny(pattern, "*?[") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, i18n.NewError("PathInvalidPattern", ElementTemplateContext{Element: fa, Extra: pattern})
+		}
+		if len(matches) > 0 {
+			paths = matches
+		}
+	}
+	for _, path := range paths {
+		if err := checkPath(path, fa, mode); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+// requirements of flag or argument are added to requirements of type; path that must not exist is not required to exist
+func effectivePathMode(type_mode PathMode, mode PathMode) PathMode {
+	if mode&PathNotExists != 0 {
+		type_mode &^= PathExists
+	}
+	return type_mode | mode
+}
+
+func checkPath(path string, fa IFlagArg, mode PathMode) error {
+	ctx := ElementTemplateContext{Element: fa, Extra: path}
+
+	if exts := fa.GetExtensions(); len(exts) > 0 {
+		ext := filepath.Ext(path)
+		allowed := false
+		for _, e := range exts {
+			if strings.EqualFold(ext, "."+strings.TrimPrefix(e, ".")) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return i18n.NewError("PathExtensionNotAllowed", ctx)
+		}
+	}
+
+	info, err := os.Stat(path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return i18n.NewError("PathNotAccessible", ctx)
+	}
+	if mode&PathExists != 0 && !exists {
+		return i18n.NewError("PathNotFound", ctx)
+	}
+	if mode&PathNotExists != 0 && exists {
+		return i18n.NewError("PathAlreadyExists", ctx)
+	}
+	if exists && mode&PathDir != 0 && !info.IsDir() {
+		return i18n.NewError("PathNotDir", ctx)
+	}
+	if exists && mode&PathRegular != 0 && !info.Mode().IsRegular() {
+		return i18n.NewError("PathNotRegular", ctx)
+	}
+	if exists && mode&PathReadable != 0 && !canRead(path, info) {
+		return i18n.NewError("PathNotReadable", ctx)
+	}
+	if mode&PathWritable != 0 && !isWritable(path, info) {
+		return i18n.NewError("PathNotWritable", ctx)
+	}
+	return nil
+}
+
+// file or directory is writable if process has write permission for it; path that does not exist is writable
+// if its directory is writable. Nothing is opened or created, so parsing has no side effects on file system
+func isWritable(path string, info fs.FileInfo) bool {
+	if info == nil {
+		dir := statOrNil(filepath.Dir(path))
+		return dir != nil && dir.IsDir() && isWritable(filepath.Dir(path), dir)
+	}
+	return canWrite(path, info)
+}
+
+func statOrNil(path string) fs.FileInfo {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	return info
+}
+
+// paths starting with prefix for shell completion; directories end with separator so completion can continue into them
+func completePaths(prefix string, fa IFlagArg, dirs_only bool) []Completion {
+	completions := make([]Completion, 0)
+	matches, _ := filepath.Glob(prefix + "*")
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			completions = append(completions, Completion{Value: m + string(filepath.Separator)})
+			continue
+		}
+		if dirs_only || checkPath(m, fa, 0) != nil {
+			// extension is not allowed
+			continue
+		}
+		completions = append(completions, Completion{Value: m})
+	}
+	return completions
+}
@@ -0,0 +1,138 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+func TestPathTypes(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("a"), 0666)
+	os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("b"), 0666)
+	os.WriteFile(filepath.Join(dir, "c.txt"), []byte("c"), 0666)
+	os.Mkdir(filepath.Join(dir, "sub"), 0777)
+	os.WriteFile(filepath.Join(dir, "locked.txt"), []byte("l"), 0444)
+
+	tests := []struct {
+		name    string
+		fa      IFlagArg
+		value   string
+		want    interface{}
+		wantErr string
+	}{
+		{name: "file", fa: &Flag[File]{Name: "f"}, value: filepath.Join(dir, "c.txt"), want: filepath.Join(dir, "c.txt")},
+		{name: "file is directory", fa: &Flag[File]{Name: "f"}, value: filepath.Join(dir, "sub"), want: filepath.Join(dir, "sub")},
+		{name: "file not found", fa: &Flag[File]{Name: "f"}, value: filepath.Join(dir, "x.txt"), wantErr: "PathNotFound"},
+		{name: "file regular", fa: &Flag[File]{Name: "f", PathMode: PathRegular}, value: filepath.Join(dir, "sub"), wantErr: "PathNotRegular"},
+		{name: "file glob", fa: &Flag[File]{Name: "f"}, value: filepath.Join(dir, "c.*"), want: filepath.Join(dir, "c.txt")},
+		{name: "file glob many", fa: &Flag[File]{Name: "f"}, value: filepath.Join(dir, "*.yaml"), wantErr: "PathMultipleMatches"},
+		{name: "file glob none", fa: &Flag[File]{Name: "f"}, value: filepath.Join(dir, "*.json"), wantErr: "PathNotFound"},
+		{name: "file extension", fa: &Flag[File]{Name: "f", Extensions: []string{"yaml", ".yml"}}, value: filepath.Join(dir, "a.yaml"), want: filepath.Join(dir, "a.yaml")},
+		{name: "file wrong extension", fa: &Flag[File]{Name: "f", Extensions: []string{"yaml", ".yml"}}, value: filepath.Join(dir, "c.txt"), wantErr: "PathExtensionNotAllowed"},
+		{
+			name:  "files expanded",
+			fa:    &Flag[[]File]{Name: "f"},
+			value: filepath.Join(dir, "*.yaml"),
+			want:  []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")},
+		},
+		{
+			name:  "files expanded with extensions",
+			fa:    &Flag[[]File]{Name: "f", Extensions: []string{".txt"}},
+			value: filepath.Join(dir, "c.*"),
+			want:  []string{filepath.Join(dir, "c.txt")},
+		},
+		{name: "files expanded wrong extension", fa: &Flag[[]File]{Name: "f", Extensions: []string{".txt"}}, value: filepath.Join(dir, "*"), wantErr: "PathExtensionNotAllowed"},
+		{name: "dir", fa: &Arg[Dir]{Name: "d"}, value: dir, want: dir},
+		{name: "dir is file", fa: &Arg[Dir]{Name: "d"}, value: filepath.Join(dir, "c.txt"), wantErr: "PathNotDir"},
+		{name: "dirs expanded", fa: &Arg[[]Dir]{Name: "d"}, value: filepath.Join(dir, "s*"), want: []string{filepath.Join(dir, "sub")}},
+		{name: "path", fa: &Flag[Path]{Name: "p"}, value: filepath.Join(dir, "new.txt"), want: filepath.Join(dir, "new.txt")},
+		{name: "path not exists", fa: &Flag[Path]{Name: "p", PathMode: PathNotExists}, value: filepath.Join(dir, "c.txt"), wantErr: "PathAlreadyExists"},
+		{name: "path writable", fa: &Flag[Path]{Name: "p", PathMode: PathWritable}, value: filepath.Join(dir, "new.txt"), want: filepath.Join(dir, "new.txt")},
+		{name: "dir writable", fa: &Arg[Dir]{Name: "d", PathMode: PathWritable}, value: filepath.Join(dir, "sub"), want: filepath.Join(dir, "sub")},
+		{name: "path in missing dir", fa: &Flag[Path]{Name: "p", PathMode: PathWritable}, value: filepath.Join(dir, "missing", "new.txt"), wantErr: "PathNotWritable"},
+		{name: "file readable", fa: &Flag[File]{Name: "f", PathMode: PathReadable}, value: filepath.Join(dir, "a.yaml"), want: filepath.Join(dir, "a.yaml")},
+		{name: "file not exists overrides type", fa: &Flag[File]{Name: "f", PathMode: PathNotExists}, value: filepath.Join(dir, "out.txt"), want: filepath.Join(dir, "out.txt")},
+	}
+	if os.Geteuid() != 0 {
+		// root can write to any file
+		tests = append(tests, struct {
+			name    string
+			fa      IFlagArg
+			value   string
+			want    interface{}
+			wantErr string
+		}{name: "file not writable", fa: &Flag[File]{Name: "f", PathMode: PathWritable}, value: filepath.Join(dir, "locked.txt"), wantErr: "PathNotWritable"})
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fa.SetValue(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("SetValue() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetValue() error = %v", err)
+			}
+			if got := tt.fa.GetValue(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// checking of writable directory leaves nothing behind
+	if entries, _ := os.ReadDir(filepath.Join(dir, "sub")); len(entries) != 0 {
+		t.Errorf("writable check created %v", entries)
+	}
+
+	// completion lists matching paths, directories can be completed further
+	sep := string(filepath.Separator)
+	got := hintCompletions(&Flag[File]{Name: "f", Extensions: []string{".yaml"}}, dir+sep)
+	want := []Completion{{Value: filepath.Join(dir, "a.yaml")}, {Value: filepath.Join(dir, "b.yaml")}, {Value: filepath.Join(dir, "sub") + sep}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hintCompletions() = %q, want %q", got, want)
+	}
+	got = hintCompletions(&Arg[Dir]{Name: "d"}, dir+sep)
+	want = []Completion{{Value: filepath.Join(dir, "sub") + sep}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hintCompletions() = %q, want %q", got, want)
+	}
+
+	// path errors reach user when flag is given on command line
+	run_tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+		wantMsg string
+	}{
+		{name: "long flag directory", args: []string{"test", "--input", filepath.Join(dir, "sub")}, wantErr: "PathNotRegular", wantMsg: "is not a regular file"},
+		{name: "long flag missing", args: []string{"test", "--input=" + filepath.Join(dir, "x.txt")}, wantErr: "PathNotFound", wantMsg: "does not exist"},
+		{name: "short flag directory", args: []string{"test", "-i", filepath.Join(dir, "sub")}, wantErr: "PathNotRegular", wantMsg: "is not a regular file"},
+		{name: "short flag missing", args: []string{"test", "-i" + filepath.Join(dir, "x.txt")}, wantErr: "PathNotFound", wantMsg: "does not exist"},
+	}
+	for _, tt := range run_tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.AddFlag(&Flag[File]{Name: "input", Short: 'i', PathMode: PathRegular})
+			errors_out := bytes.NewBuffer(nil)
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(errors_out)
+			err := app.Run(tt.args)
+			var int_err *i18n.Error
+			if !errors.As(err, &int_err) || int_err.GetKey() != tt.wantErr {
+				t.Fatalf("Run() error = %v, want %s", err, tt.wantErr)
+			}
+			if !strings.Contains(errors_out.String(), tt.wantMsg) {
+				t.Errorf("unexpected error output %q", errors_out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package gocli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+// number of times user is asked again after invalid answer
+const promptAttempts = 3
+
+// IPrompter asks user for values of missing required flags and arguments, see Application.PromptForMissing.
+// Question is localized text made from PromptTemplate or PromptSelectTemplate
+type IPrompter interface {
+	// Prompt shows question and returns answer without line end; answer must not be echoed if secret is set
+	Prompt(question string, secret bool) (string, error)
+}
+
+// asks questions on terminal
+type terminalPrompter struct {
+	in     *os.File
+	out    io.Writer
+	reader *bufio.Reader
+}
+
+func newTerminalPrompter(in *os.File, out io.Writer) *terminalPrompter {
+	return &terminalPrompter{in: in, out: out, reader: bufio.NewReader(in)}
+}
+
+func (p *terminalPrompter) Prompt(question string, secret bool) (string, error) {
+	fmt.Fprint(p.out, question)
+	if secret {
+		if restore, err := disableEcho(p.in); err == nil {
+			defer func() {
+				restore()
+				// new line typed by user was not echoed
+				fmt.Fprintln(p.out)
+			}()
+		}
+	}
+	line, err := p.reader.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// asks for value of missing required flag or argument. Invalid answers are reported and asked again;
+// if user gives no answer element stays not set
+func (ctx *context) promptFor(app *Application, fa IFlagArg) error {
+	prompt_ctx := PromptTemplateContext{Element: fa, Name: elementName(fa), Default: redactValue(fa, fa.GetDefault())}
+	tpl := "PromptTemplate"
+	if hints := fa.GetHints(); len(hints) > 0 {
+		tpl = "PromptSelectTemplate"
+		for i, h := range hints {
+			option := PromptOption{Number: i + 1, Value: h}
+			if enum := fa.GetEnum(); enum != nil {
+				option.Description = enum.GetDescription(h)
+			}
+			prompt_ctx.Options = append(prompt_ctx.Options, option)
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := app.templateManager.doFormatTemplate(buf, tpl, prompt_ctx); err != nil {
+		return err
+	}
+
+	var err error
+	for attempt := 0; attempt < promptAttempts; attempt++ {
+		answer, prompt_err := ctx.prompter.Prompt(buf.String(), fa.IsSecret())
+		if errors.Is(prompt_err, io.EOF) {
+			// input is closed, there is no answer
+			return err
+		}
+		if prompt_err != nil {
+			return i18n.NewError("PromptFailed", ElementTemplateContext{Element: fa, Extra: prompt_err.Error()})
+		}
+		if !fa.IsSecret() {
+			answer = strings.TrimSpace(answer)
+		}
+		if answer == "" {
+			answer = fa.GetDefault()
+		}
+		if answer == "" {
+			continue
+		}
+		// selection menu accepts number of option
+		if n, conv_err := strconv.Atoi(answer); conv_err == nil && n >= 1 && n <= len(prompt_ctx.Options) {
+			answer = prompt_ctx.Options[n-1].Value
+		}
+
+		fa.Clear()
+		if err = fa.SetValue(answer); err == nil {
+			fa.setSource(SourcePrompt)
+			if err = fa.ValidateWrapper(app); err == nil {
+				return nil
+			}
+		}
+		app.printError(err)
+	}
+	return err
+}
@@ -51,6 +51,12 @@ type _ListItem struct {
 	_Element
 }
 
+// column where text of item starts; first line of item is indented when written and again when item is closed,
+// following lines and blocks inside item, i.e. table, have to be indented to this column
+func (e *_ListItem) TextColumn() int {
+	return 2*e.textIndent + utf8.RuneCountInString(e.textPrefix)
+}
+
 func (e *_ListItem) Literal(literal string) int {
 	indent := e.textIndent
 	prefix := e.textPrefix
@@ -63,7 +69,7 @@ func (e *_ListItem) Literal(literal string) int {
 		use_prefix := prefix
 		if i > 0 {
 			e.CR()
-			use_indent = indent + utf8.RuneCountInString(prefix)
+			use_indent = e.TextColumn()
 			use_prefix = ""
 		}
 		lastOutputLen = e.Out(use_indent, fmt.Sprintf("%s%s", use_prefix, l))
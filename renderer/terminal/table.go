@@ -2,6 +2,7 @@ package terminal
 
 import (
 	"bytes"
+	"strings"
 
 	"github.com/ez-leka/gocli/renderer"
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -122,6 +123,11 @@ func (t *_Table) Close() renderer.IElement {
 
 	s := t.tw.Render()
 
+	// table in list item, i.e. in definition, is aligned with text of the item
+	if item, ok := t.Parent().(*_ListItem); ok {
+		indent := strings.Repeat(" ", item.TextColumn())
+		s = indent + strings.ReplaceAll(s, "\n", "\n"+indent)
+	}
 	t.Parent().Out(0, s)
 
 	return t.Parent()
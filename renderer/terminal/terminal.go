@@ -291,6 +291,7 @@ func skipParagraphTags(node *blackfriday.Node) bool {
 	if grandparent == nil || grandparent.Type != blackfriday.List {
 		return false
 	}
-	tightOrTerm := grandparent.Tight || node.Parent.ListFlags&blackfriday.ListTypeTerm != 0
+	// definition can contain blocks such as table, its text is still shown as in tight list
+	tightOrTerm := grandparent.Tight || node.Parent.ListFlags&(blackfriday.ListTypeTerm|blackfriday.ListTypeDefinition) != 0
 	return grandparent.Type == blackfriday.List && tightOrTerm
 }
@@ -0,0 +1,98 @@
+package gocli
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+// shown instead of value of secret flag or argument
+const redactedValue = "******"
+
+// flag added for every secret flag to read its value from file, i.e. --token-file for --token
+type secretFileFlag struct {
+	*Flag[File]
+	secret IFlag
+}
+
+// value of secret flag or argument is replaced with redactedValue
+func redactValue(fa IFlagArg, value string) string {
+	if fa.IsSecret() && value != "" {
+		return redactedValue
+	}
+	return value
+}
+
+// replaces value of secret flag or argument in context of gocli error
+func redactError(fa IFlagArg, err error) error {
+	var int_err *i18n.Error
+	if err == nil || !fa.IsSecret() || !errors.As(err, &int_err) {
+		return err
+	}
+	switch data := int_err.GetData().(type) {
+	case ElementTemplateContext:
+		data.Extra = redactValue(fa, data.Extra)
+		return i18n.NewError(int_err.GetKey(), data)
+	case SourceTemplateContext:
+		data.Extra = redactValue(fa, data.Extra)
+		return i18n.NewError(int_err.GetKey(), data)
+	}
+	return err
+}
+
+// adds file flag for every secret flag of command and its sub-commands unless command has flag with the same name
+func (a *Application) addSecretFileFlags(c *Command) {
+	for _, f := range c.Flags {
+		if !f.IsSecret() {
+			continue
+		}
+		name := a.templateManager.GetLocalizedString("SecretFileFlagName", f.GetName())
+		exists := false
+		for _, other := range c.Flags {
+			if other.GetName() == name {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+		c.Flags = append(c.Flags, &secretFileFlag{
+			Flag: &Flag[File]{
+				Name:             name,
+				Usage:            a.templateManager.GetLocalizedString("SecretFileFlagUsage", f.GetName()),
+				PathMode:         PathReadable | PathRegular,
+				Hidden:           f.IsHidden(),
+				ValidationGroups: f.GetValidationGroups(),
+			},
+			secret: f,
+		})
+	}
+	for _, sc := range c.Commands {
+		a.addSecretFileFlags(sc)
+	}
+}
+
+// sets secret flags from files given on command line
+func (ctx *context) readSecretFiles() error {
+	for name, f := range ctx.flags_lookup {
+		sf, ok := f.(*secretFileFlag)
+		if !ok || name != sf.GetName() || !sf.IsSetByUser() {
+			continue
+		}
+		if sf.secret.IsSetByUser() {
+			return i18n.NewError("FlagsArgsFromMultipleGroups", TokenTemplateContext{Name: "--" + sf.secret.GetName(), Extra: "--" + sf.GetName()})
+		}
+		path := sf.GetValue().(string)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return i18n.NewError("SecretFileReadFailed", ElementTemplateContext{Element: sf.secret, Extra: path})
+		}
+		if err = sf.secret.SetValue(strings.TrimRight(string(content), "\r\n")); err != nil {
+			return i18n.NewError("FlagValidationFailed", ElementTemplateContext{Element: sf.secret, Extra: redactedValue})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package gocli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+func TestSecretFlags(t *testing.T) {
+	dir := t.TempDir()
+	token_path := filepath.Join(dir, "token")
+	os.WriteFile(token_path, []byte("s3cr3t\n"), 0600)
+	bad_path := filepath.Join(dir, "bad")
+	os.WriteFile(bad_path, []byte("hunter2"), 0600)
+
+	tests := []struct {
+		name      string
+		args      []string
+		env       string
+		wantToken string
+		wantPin   int
+		wantErr   bool
+	}{
+		{name: "command line", args: []string{"test", "run", "--token", "abc"}, wantToken: "abc"},
+		{name: "from file", args: []string{"test", "run", "--token-file", token_path}, wantToken: "s3cr3t"},
+		{name: "pin from file", args: []string{"test", "run", "--token", "abc", "--pin-file=" + token_path}, wantErr: true},
+		{name: "both set", args: []string{"test", "run", "--token", "abc", "--token-file", token_path}, wantErr: true},
+		{name: "missing file", args: []string{"test", "run", "--token-file", filepath.Join(dir, "missing")}, wantErr: true},
+		{name: "invalid value", args: []string{"test", "run", "--token", "abc", "--pin", "hunter2"}, wantErr: true},
+		{name: "invalid value in file", args: []string{"test", "run", "--token", "abc", "--pin-file", bad_path}, wantErr: true},
+		{name: "invalid value in environment", args: []string{"test", "run", "--token", "abc"}, env: "hunter2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("GOCLI_TEST_PIN", tt.env)
+			}
+			app := New()
+			app.AddCommand(Command{
+				Name: "run",
+				Flags: []IFlag{
+					&Flag[String]{Name: "token", Secret: true, Required: true},
+					&Flag[Int]{Name: "pin", Secret: true, EnvVars: []string{"GOCLI_TEST_PIN"}},
+				},
+			})
+			app.SetWriter(bytes.NewBuffer(nil))
+			errs := bytes.NewBuffer(nil)
+			app.SetErrorWriter(errs)
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Contains(errs.String(), "hunter2") || strings.Contains(errs.String(), "s3cr3t") {
+				t.Errorf("secret value is shown in error:\n%s", errs.String())
+			}
+			if err == nil {
+				if got, _ := app.GetFlagValue("token"); got != tt.wantToken {
+					t.Errorf("token = %v, want %v", got, tt.wantToken)
+				}
+			}
+		})
+	}
+
+	// value is redacted in error context, help and string form
+	pin := &Flag[Int]{Name: "pin", Secret: true, Default: "1234"}
+	pin.Clear()
+	var int_err *i18n.Error
+	if err := pin.SetValue("hunter2"); !errors.As(err, &int_err) || int_err.GetData().(ElementTemplateContext).Extra != redactedValue {
+		t.Errorf("SetValue() error = %v, want redacted value", err)
+	}
+	pin.SetValue("42")
+	if got := fmt.Sprint(pin); got != "--pin="+redactedValue {
+		t.Errorf("String() = %s, want --pin=%s", got, redactedValue)
+	}
+
+	app := New()
+	app.AddFlag(pin)
+	out := bytes.NewBuffer(nil)
+	app.SetWriter(out)
+	app.Run([]string{"test", "--help"})
+	if strings.Contains(out.String(), "1234") || !strings.Contains(out.String(), "(Default: "+redactedValue+")") {
+		t.Errorf("default of secret flag is not redacted in help:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "--pin-file") {
+		t.Errorf("help does not show --pin-file:\n%s", out.String())
+	}
+
+	// value is redacted in errors of validators
+	validate := func(fa IFlagArg) error {
+		if !fa.IsSetByUser() {
+			return nil
+		}
+		return i18n.NewError("FlagValidationFailed", ElementTemplateContext{Element: fa, Extra: fa.GetValue().(string)})
+	}
+	app = New()
+	app.AddCommand(Command{
+		Name:  "run",
+		Flags: []IFlag{&Flag[String]{Name: "token", Secret: true, Validator: func(a *Application, f IFlag) error { return validate(f) }}},
+		Args:  []IArg{&Arg[String]{Name: "password", Secret: true, Validator: func(a *Application, arg IArg) error { return validate(arg) }}},
+	})
+	for _, args := range [][]string{{"test", "run", "--token", "hunter2"}, {"test", "run", "hunter2"}} {
+		errs := bytes.NewBuffer(nil)
+		app.SetWriter(bytes.NewBuffer(nil))
+		app.SetErrorWriter(errs)
+		err := app.Run(args)
+		if !errors.As(err, &int_err) || int_err.GetData().(ElementTemplateContext).Extra != redactedValue {
+			t.Errorf("Application.Run() error = %v, want redacted value", err)
+		}
+		if strings.Contains(errs.String(), "hunter2") {
+			t.Errorf("secret value is shown in error:\n%s", errs.String())
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package gocli
+
+import (
+	"io"
+	"os"
+	"reflect"
+)
+
+// name of standard input or output on command line
+const stdioName = "-"
+
+// InputFile is a file to read from, - stands for standard input. GetValue returns io.ReadCloser,
+// file is opened on first read and is closed by application after command actions are executed
+type InputFile struct {
+	name   string
+	reader io.ReadCloser
+}
+
+// OutputFile is a file to write to, - stands for standard output. GetValue returns io.WriteCloser,
+// file is created on first write and is closed by application after command actions are executed
+type OutputFile struct {
+	name   string
+	writer io.WriteCloser
+}
+
+// files of flags and arguments closed after run
+type closeAfterRun interface {
+	closeFile() error
+}
+
+func (s *InputFile) GetReturnType() reflect.Type {
+	return reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+}
+
+func (s *InputFile) FromString(v string, fa IFlagArg) error {
+	if v != stdioName {
+		path, err := parsePath(v, fa, PathExists|PathReadable)
+		if err != nil {
+			return err
+		}
+		v = path
+	}
+	*s = InputFile{name: v}
+	return nil
+}
+
+func (s *InputFile) GetValue() interface{} {
+	if s.name == "" {
+		return nil
+	}
+	return io.ReadCloser(s)
+}
+
+// Name returns path of the file or - for standard input
+func (s *InputFile) Name() string {
+	return s.name
+}
+
+func (s *InputFile) Read(p []byte) (int, error) {
+	if s.reader == nil {
+		if s.name == stdioName {
+			s.reader = io.NopCloser(os.Stdin)
+		} else {
+			f, err := os.Open(s.name)
+			if err != nil {
+				return 0, err
+			}
+			s.reader = f
+		}
+	}
+	return s.reader.Read(p)
+}
+
+// Close closes file if it was opened; standard input is never closed
+func (s *InputFile) Close() error {
+	if s.reader == nil {
+		return nil
+	}
+	err := s.reader.Close()
+	s.reader = nil
+	return err
+}
+
+func (s *InputFile) closeFile() error {
+	return s.Close()
+}
+
+func (s *InputFile) shellCompletion(fa IFlagArg) ([]Completion, CompletionDirective) {
+	return extensionCompletions(fa), CompletionFiles
+}
+
+func (s *OutputFile) GetReturnType() reflect.Type {
+	return reflect.TypeOf((*io.WriteCloser)(nil)).Elem()
+}
+
+func (s *OutputFile) FromString(v string, fa IFlagArg) error {
+	if v != stdioName {
+		path, err := parsePath(v, fa, PathWritable)
+		if err != nil {
+			return err
+		}
+		v = path
+	}
+	*s = OutputFile{name: v}
+	return nil
+}
+
+func (s *OutputFile) GetValue() interface{} {
+	if s.name == "" {
+		return nil
+	}
+	return io.WriteCloser(s)
+}
+
+// Name returns path of the file or - for standard output
+func (s *OutputFile) Name() string {
+	return s.name
+}
+
+func (s *OutputFile) Write(p []byte) (int, error) {
+	if s.writer == nil {
+		if s.name == stdioName {
+			s.writer = nopWriteCloser{os.Stdout}
+		} else {
+			f, err := os.OpenFile(s.name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+			if err != nil {
+				return 0, err
+			}
+			s.writer = f
+		}
+	}
+	return s.writer.Write(p)
+}
+
+// Close closes file if it was created; standard output is never closed
+func (s *OutputFile) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
+
+func (s *OutputFile) closeFile() error {
+	return s.Close()
+}
+
+func (s *OutputFile) shellCompletion(fa IFlagArg) ([]Completion, CompletionDirective) {
+	return extensionCompletions(fa), CompletionFiles
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// closes input and output files of all parsed flags and arguments, first error is returned
+func (ctx *context) closeFiles() error {
+	var first_err error
+	closeValue := func(v reflect.Value) {
+		if c, ok := v.Interface().(closeAfterRun); ok {
+			if err := c.closeFile(); err != nil && first_err == nil {
+				first_err = err
+			}
+		}
+	}
+
+	closed := make(map[IFlagArg]bool)
+	elements := make([]IFlagArg, 0, len(ctx.flags_lookup)+len(ctx.arguments_lookup))
+	for _, f := range ctx.flags_lookup {
+		elements = append(elements, f)
+	}
+	for _, a := range ctx.arguments_lookup {
+		elements = append(elements, a)
+	}
+	for _, fa := range elements {
+		// short and long names point to the same flag
+		if closed[fa] {
+			continue
+		}
+		closed[fa] = true
+
+		rv := reflect.ValueOf(fa.getDestination())
+		if rv.Elem().Kind() == reflect.Slice {
+			for i := 0; i < rv.Elem().Len(); i++ {
+				closeValue(rv.Elem().Index(i).Addr())
+			}
+		} else {
+			closeValue(rv)
+		}
+	}
+	return first_err
+}
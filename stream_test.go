@@ -0,0 +1,120 @@
+package gocli
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInputOutputFiles(t *testing.T) {
+	dir := t.TempDir()
+	in_path := filepath.Join(dir, "in.txt")
+	out_path := filepath.Join(dir, "out.txt")
+	os.WriteFile(in_path, []byte("from file"), 0666)
+
+	// replace stdin and stdout with pipes
+	stdin_r, stdin_w, _ := os.Pipe()
+	stdout_r, stdout_w, _ := os.Pipe()
+	orig_stdin, orig_stdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin_r, stdout_w
+	defer func() { os.Stdin, os.Stdout = orig_stdin, orig_stdout }()
+	stdin_w.Write([]byte("from stdin"))
+	stdin_w.Close()
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "file to file", args: []string{"test", "copy", in_path, "--output", out_path}, want: "from file"},
+		{name: "stdin to file", args: []string{"test", "copy", "-", "--output", out_path}, want: "from stdin"},
+		{name: "file to stdout", args: []string{"test", "copy", in_path, "--output", "-"}, want: "from file"},
+		{name: "missing input", args: []string{"test", "copy", filepath.Join(dir, "missing.txt"), "--output", out_path}, wantErr: true},
+		{name: "output in missing dir", args: []string{"test", "copy", in_path, "--output", filepath.Join(dir, "missing", "out.txt")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(out_path)
+			input := &Arg[InputFile]{Name: "input"}
+			output := &Flag[OutputFile]{Name: "output"}
+			app := New()
+			app.AddCommand(Command{
+				Name:  "copy",
+				Args:  []IArg{input},
+				Flags: []IFlag{output},
+				Action: func(a *Application, c *Command, i interface{}) (interface{}, error) {
+					in, _ := a.GetArgumentValue("input")
+					out, _ := a.GetFlagValue("output")
+					_, err := io.Copy(out.(io.WriteCloser), in.(io.ReadCloser))
+					return nil, err
+				},
+			})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			// files are closed after run
+			if input.Destination.reader != nil || output.Destination.writer != nil {
+				t.Errorf("files were not closed after run")
+			}
+			var got []byte
+			if output.Destination.Name() == stdioName {
+				stdout_w.Close()
+				got, _ = io.ReadAll(stdout_r)
+			} else {
+				got, _ = os.ReadFile(out_path)
+			}
+			if string(got) != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplication_ExpandAtFiles(t *testing.T) {
+	dir := t.TempDir()
+	token_path := filepath.Join(dir, "token")
+	os.WriteFile(token_path, []byte("s3cr3t\n"), 0600)
+
+	tests := []struct {
+		name    string
+		expand  bool
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "long flag", expand: true, args: []string{"test", "run", "--token", "@" + token_path}, want: "s3cr3t"},
+		{name: "long flag with =", expand: true, args: []string{"test", "run", "--token=@" + token_path}, want: "s3cr3t"},
+		{name: "short flag", expand: true, args: []string{"test", "run", "-t@" + token_path}, want: "s3cr3t"},
+		{name: "escaped", expand: true, args: []string{"test", "run", "--token", "@@home"}, want: "@home"},
+		{name: "missing file", expand: true, args: []string{"test", "run", "--token", "@" + filepath.Join(dir, "missing")}, wantErr: true},
+		{name: "disabled", args: []string{"test", "run", "--token", "@" + token_path}, want: "@" + token_path},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := New()
+			app.ExpandAtFiles = tt.expand
+			app.AddFlag(&Flag[String]{Name: "token", Short: 't'})
+			app.AddCommand(Command{Name: "run"})
+			app.SetWriter(bytes.NewBuffer(nil))
+			app.SetErrorWriter(bytes.NewBuffer(nil))
+			err := app.Run(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Application.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got, _ := app.GetFlagValue("token"); got != tt.want {
+					t.Errorf("token = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -4,38 +4,185 @@ import "github.com/ez-leka/gocli/i18n"
 
 var GoCliStrings = i18n.Entries{
 	"BashCompletionTemplate": `
-	#/usr/bin/env bash
-	_{{.Name}}_bash_autocomplete() {
-		local cur prev opts base
-		COMPREPLY=()
-		cur="{{printf "\x24"}}{{printf "\x7B"}}COMP_WORDS[COMP_CWORD]{{printf "\x7D"}}"
-		opts=$( {{printf "\x24"}}{{printf "\x7B"}}COMP_WORDS[0]{{printf "\x7D"}} --bash-completions "{{printf "\x24"}}{{printf "\x7B"}}COMP_WORDS[@]:1:$COMP_CWORD{{printf "\x7D"}}" )
-		COMPREPLY=( $(compgen -W "{{printf "\x24"}}{{printf "\x7B"}}opts{{printf "\x7D"}}" -- {{printf "\x24"}}{{printf "\x7B"}}cur{{printf "\x7D"}}) )
-		return 0
-	}
-	complete -F _{{.Name}}_bash_autocomplete -o default {{.Path}}
-	
-	`,
+# bash completion for {{.Name}}, add to ~/.bashrc: source <({{.Name}} generate-completion bash)
+_{{.Name}}_completions() {
+	local line={{"$"}}{COMP_LINE:0:COMP_POINT}
+	local -a words
+	read -r -a words <<< "$line"
+	if [[ -z $line || $line == *[[:space:]] ]]; then
+		words+=("")
+	fi
+	local cur={{"$"}}{words[{{"$"}}{#words[@]}-1]}
+
+	local out
+	out=$("{{"$"}}{words[0]}" __complete "{{"$"}}{words[@]:1}" 2>/dev/null) || return
+	local -a lines
+	mapfile -t lines <<< "$out"
+	local directive={{"$"}}{lines[{{"$"}}{#lines[@]}-1]#:}
+	unset 'lines[{{"$"}}{#lines[@]}-1]'
+	local -a candidates=("{{"$"}}{lines[@]%%%%$'\t'*}")
+
+	local -a replies=()
+	if (( directive & 6 )); then
+		# file names, value of --flag=value is completed
+		local value=$cur
+		[[ $cur == -*=* ]] && value={{"$"}}{cur#*=}
+		local flag={{"$"}}{cur%%"$value"}
+		local file ext
+		compopt -o filenames 2>/dev/null
+		while IFS= read -r file; do
+			if (( directive & 2 )) && [[ ! -d $file && {{"$"}}{#candidates[@]} -gt 0 ]]; then
+				for ext in "{{"$"}}{candidates[@]}"; do
+					[[ $file == *."$ext" ]] && break
+				done
+				[[ $file == *."$ext" ]] || continue
+			fi
+			replies+=("$flag$file")
+		done < <(if (( directive & 4 )); then compgen -d -- "$value"; else compgen -f -- "$value"; fi)
+	else
+		replies=("{{"$"}}{candidates[@]}")
+	fi
+
+	# bash breaks words on = and : so only the part after them is replaced
+	local word={{"$"}}{COMP_WORDS[COMP_CWORD]}
+	COMPREPLY=("{{"$"}}{replies[@]#"{{"$"}}{cur%%"$word"}"}")
+	if (( directive & 1 )); then
+		compopt -o nospace 2>/dev/null
+	fi
+}
+complete -F _{{.Name}}_completions {{.Name}}
+`,
+	"ZshCompletionTemplate": `
+#compdef {{.Name}}
+# zsh completion for {{.Name}}, add to ~/.zshrc: source <({{.Name}} generate-completion zsh)
+_{{.Name}}() {
+	local out directive item value
+	local -a lines candidates
+	out=$("{{"$"}}{words[1]}" __complete "{{"$"}}{(@)words[2,CURRENT]}" 2>/dev/null) || return 1
+	lines=("{{"$"}}{(@f)out}")
+	directive={{"$"}}{lines[-1]#:}
+	lines=("{{"$"}}{(@)lines[1,-2]}")
 
-	"ZshCompletionTemplate": `#compdef {{.Name}}
-	
-	_{{.Name}}() {{printf "\x7B"}}
-		local matches=($({{printf "\x24"}}{{printf "\x7B"}}words[1]{{printf "\x7D"}} --completion-bash "{{printf "\x24"}}{{printf "\x7B"}}(@)words[2,$CURRENT]{{printf "\x7D"}}"))
-		compadd -a matches
-	
-		if [[ $compstate[nmatches] -eq 0 && $words[$CURRENT] != -* ]]; then
+	if (( directive & 6 )); then
+		# file names, value of --flag=value is completed
+		[[ {{"$"}}{words[CURRENT]} == -*=* ]] && compset -P '*='
+		if (( directive & 4 )); then
+			_files -/
+		elif (( {{"$"}}{#lines} )); then
+			_files -g "*.({{"$"}}{(j:|:)lines})"
+		else
 			_files
 		fi
-	{{printf "\x7D"}}
-	
-	if [[ "{{printf "\x24"}}{{printf "\x28"}}basename -- {{printf "\x24"}}{{printf "\x7B"}}(%%):-%%x{{printf "\x7D"}})" != "_{{.Name}}" ]]; then
-		compdef _{{.Name}} {{.Name}}
-	fi`,
+		return
+	fi
+
+	for item in "{{"$"}}{lines[@]}"; do
+		value={{"$"}}{item%%%%$'\t'*}
+		value={{"$"}}{value//:/\\:}
+		if [[ $item == *$'\t'* ]]; then
+			candidates+=("$value:{{"$"}}{item#*$'\t'}")
+		else
+			candidates+=("$value")
+		fi
+	done
+	if (( directive & 1 )); then
+		_describe -t values value candidates -S ''
+	else
+		_describe -t values value candidates
+	fi
+}
+
+if [[ $zsh_eval_context[-1] == loadautofunc ]]; then
+	_{{.Name}} "$@"
+else
+	compdef _{{.Name}} {{.Name}}
+fi
+`,
+	"FishCompletionTemplate": `
+# fish completion for {{.Name}}, add to ~/.config/fish/config.fish: {{.Name}} generate-completion fish | source
+function __{{.Name}}_complete
+	set -l args (commandline -opc)
+	set -l cur (commandline -ct)
+	set -l prog $args[1]
+	set -e args[1]
+	set -l lines ($prog __complete $args $cur 2>/dev/null)
+	or return
+	set -l directive (string replace -- ':' '' $lines[-1])
+	set -e lines[-1]
+
+	if test (math "bitand($directive, 6)") -ne 0
+		# file names, value of --flag=value is completed
+		set -l flag (string match -r -- '^-[^=]*=' $cur)
+		set -l value (string replace -r -- '^-[^=]*=' '' $cur)
+		for path in (__fish_complete_path $value)
+			set -l name (string split -f1 \t -- $path)
+			if string match -q -- '*/' $name
+				echo $flag$path
+			else if test (math "bitand($directive, 2)") -ne 0
+				set -l ext (string match -r -- '\.([^./]*)$' $name)[2]
+				if test (count $lines) -eq 0; or contains -- "$ext" $lines
+					echo $flag$path
+				end
+			end
+		end
+		return
+	end
+	printf '%%s\n' $lines
+end
+
+complete -c {{.Name}} -f -a '(__{{.Name}}_complete)'
+`,
+	"PowershellCompletionTemplate": `
+# PowerShell completion for {{.Name}}, add to $PROFILE: {{.Name}} generate-completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName '{{.Name}}' -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+
+	$words = @($commandAst.CommandElements | Where-Object { $_.Extent.StartOffset -lt $cursorPosition } | ForEach-Object { $_.Extent.Text })
+	if ($wordToComplete -eq '') {
+		# older PowerShell drops empty arguments of native commands
+		if ($PSVersionTable.PSVersion -lt [version]'7.3') { $words += '""' } else { $words += '' }
+	}
+	$prog = $words[0]
+	$rest = @($words | Select-Object -Skip 1)
+	$lines = @(& $prog __complete @rest 2>$null)
+	if ($lines.Count -eq 0) { return }
+	$directive = [int]$lines[-1].TrimStart(':')
+	$lines = @($lines | Select-Object -SkipLast 1)
+
+	if ($directive -band 6) {
+		# file names, value of --flag=value is completed
+		$flag = ''
+		$value = $wordToComplete
+		if ($wordToComplete -match '^(-[^=]*=)(.*)$') {
+			$flag = $Matches[1]
+			$value = $Matches[2]
+		}
+		foreach ($item in [System.Management.Automation.CompletionCompleters]::CompleteFilename($value)) {
+			$is_dir = $item.ResultType -eq 'ProviderContainer'
+			if (-not $is_dir -and ($directive -band 4)) { continue }
+			if (-not $is_dir -and $lines.Count -gt 0 -and $lines -notcontains [IO.Path]::GetExtension($item.ListItemText).TrimStart('.')) { continue }
+			[System.Management.Automation.CompletionResult]::new($flag + $item.CompletionText, $item.ListItemText, $item.ResultType, $item.ToolTip)
+		}
+		return
+	}
+	foreach ($line in $lines) {
+		$text, $description = $line -split [char]9, 2
+		if (-not $description) { $description = $text }
+		[System.Management.Automation.CompletionResult]::new($text, $text, 'ParameterValue', $description)
+	}
+}
+`,
+	"PromptTemplate": `Enter {{.Name}}{{if .Element.GetUsage}} ({{.Element.GetUsage}}){{end}}{{if .Default}} [{{.Default}}]{{end}}: `,
+	"PromptSelectTemplate": `{{.Name}}{{if .Element.GetUsage}} ({{.Element.GetUsage}}){{end}}:
+{{range .Options}}  {{.Number}}) {{.Value}}{{if .Description}} - {{.Description}}{{end}}
+{{end}}Choose number or value{{if .Default}} [{{.Default}}]{{end}}: `,
 	"CmdFlagTemplate": `
 {{- define "CmdFlag"}}
-{{- if .GetShort}} -{{.GetShort|Rune}}{{else}} --{{.GetName}}{{end -}}
-{{- if not .IsBool}}[=]<{{.GetPlaceholder}}>{{end}}{{if .IsCumulative}}...{{end -}}
+{{- if .GetShort}} -{{.GetShort|Rune}}{{else}} --{{if .IsNegatable}}[no-]{{end}}{{.GetName}}{{end -}}
+{{- if not (or .IsBool .IsCounter)}}[=]<{{.GetPlaceholder}}>{{end}}{{if or .IsCumulative .IsCounter}}...{{end -}}
 {{end -}}`,
+	"DidYouMeanTemplate": `
+{{- define "DidYouMean"}}did you mean {{range $i, $s := .}}{{if $i}} or {{end}}{{$s}}{{end}}?{{end -}}`,
 	"CmdArgTemplate": `
 {{- define "CmdArg"}}<{{.GetPlaceholder}}>{{end -}}
 `,
@@ -95,9 +242,12 @@ var GoCliStrings = i18n.Entries{
 {{- if gt (len $groups.Groups) 1}} ({{end -}}
   {{- range $groups.Groups -}}
   {{- if eq $group_idx 1}} | {{end -}}
-  {{- template "CmdGroup" Dict "Group" . "Level" .Level -}}{{$group_idx = 1}}
+  {{- template "CmdGroup" Dict "Group" . "Level" $.Level -}}{{$group_idx = 1}}
   {{end -}}
   {{- if gt (len $groups.Groups) 1}} ){{end -}}
+{{- range Constraints .CurrentCommand}}
+{{.}}
+{{- end}}
 {{BlockBracket}}
 {{if and .CurrentCommand.Description .DocGeneration}}
 {{- if eq .Level  0}}
@@ -118,22 +268,28 @@ Use "{{.AppName}} options" for a list of global command-line options (applies to
 {{end}}
 {{end}}
 `,
-	"ShellCompletionCommand":           `generate-completion`,
-	"ShellCompletionCommandDesc":       `generate completion script for bash or zch shell`,
-	"ShellCompletionFlagUsageTemplate": `used in dynamic bash completion`,
-	"ShellCompletionArgName":           `shell`,
-	"ShellCompetionArgUsage":           `type of shell for which to generate complition script`,
-	"DocGenerationCommand":             `generate-documentation`,
-	"DocGenerationCommandDesc":         `Generate documentation in specified format`,
-	"DocGenerationFormatArgName":       `format`,
-	"DocGenerationFormatArgUsage":      `Format of documenation to be generated.`,
-	"DocGenerationCssFlagName":         `css`,
-	"DocGenerationCssFlagUsage":        `path to CSS stylesheet (applies to HTML only).`,
-	"DocGenerationIconFlagName":        `icon`,
-	"DocGenerationIconFlagUsage":       `path to image to be sed as browser icon (applies to HTML only).`,
-	"DocGenerationTocFlagName":         `toc`,
-	"DocGenerationTocFlagUsage":        `if set, TOC will be generated (applies to HTML only)`,
+	"ShellCompletionCommand":          `generate-completion`,
+	"ShellCompletionCommandDesc":      `generate completion script for bash, zsh, fish or PowerShell`,
+	"ShellCompletionArgName":          `shell`,
+	"ShellCompletionOutputFlagName":   `output`,
+	"ShellCompletionOutputFlagUsage":  `file to write completion script to instead of standard output`,
+	"ShellCompletionInstallFlagName":  `install`,
+	"ShellCompletionInstallFlagUsage": `write completion script to per-user completion directory of the shell`,
+	"ShellCompletionInstalled":        `completion script written to %s`,
+	"ShellCompetionArgUsage":          `type of shell for which to generate complition script`,
+	"DocGenerationCommand":            `generate-documentation`,
+	"DocGenerationCommandDesc":        `Generate documentation in specified format`,
+	"DocGenerationFormatArgName":      `format`,
+	"DocGenerationFormatArgUsage":     `Format of documenation to be generated.`,
+	"DocGenerationCssFlagName":        `css`,
+	"DocGenerationCssFlagUsage":       `path to CSS stylesheet (applies to HTML only).`,
+	"DocGenerationIconFlagName":       `icon`,
+	"DocGenerationIconFlagUsage":      `path to image to be sed as browser icon (applies to HTML only).`,
+	"DocGenerationTocFlagName":        `toc`,
+	"DocGenerationTocFlagUsage":       `if set, TOC will be generated (applies to HTML only)`,
 
+	"SecretFileFlagName":          `%s-file`,
+	"SecretFileFlagUsage":         `read value of --%s from file`,
 	"HelpCommandAndFlagName":      `help`,
 	"HelpFlagShort":               `h`,
 	"HelpCommandUsageTemplate":    `show help`,
@@ -148,25 +304,65 @@ Use "{{.AppName}} options" for a list of global command-line options (applies to
 	"Error":                         "Error: %s",
 	"FlagLongExistsTemplate":        `flag --{{.Name}} already exists`,
 	"FlagShortExistsTemplate":       `flag -{{.Short|Rune}} already exists`,
-	"UnknownElementTemplate":        `unknown {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"UnknownElementTemplate":        `unknown {{.Element.GetType}} {{.Element.GetPlaceholder}}{{if .Suggestions}}, {{template "DidYouMean" .Suggestions}}{{end}}`,
 	"ExtraArgument":                 `unexpected argument {{.Extra}}`,
-	"UnexpectedFlagValueTemplate":   `expected argument for flag --{{.Element.Name}} {{if .Element.Short}}(-{{.Element.Short|Rune}}{{end}}) {{if .Extra}got '{{.Extra}}'{{end}}}}`,
-	"UnexpectedTokenTemplate":       `expected {{.Extra}} but got {{.Name}}`,
+	"UnexpectedFlagValueTemplate":   `expected argument for flag --{{.Element.Name}}{{if .Element.Short}} (-{{.Element.Short|Rune}}){{end}}{{if .Extra}} got '{{.Extra}}'{{end}}`,
+	"AmbiguousPrefixTemplate":       `{{.Extra}} {{.Name}} is ambiguous, could be {{range $i, $s := .Suggestions}}{{if $i}}, {{end}}{{$s}}{{end}}`,
+	"UnexpectedTokenTemplate":       `expected {{.Extra}} but got {{.Name}}{{if .Suggestions}}, {{template "DidYouMean" .Suggestions}}{{end}}`,
 	"WrongElementTypeTemplate":      `wrong {{.Element.GetType}} type for {{.Element.Name}}`,
 	"FlagAlreadySet":                `flag {{.GetName}} already have been set. This flag is not cumulative and can only appear once on command line`,
 	"NoHintsForOneOf":               `no hints speciffied for {{.GetType}} {{.GetName}}`,
+	"UnknownEnumValue":              `unsupported value {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}; use one of {{range $i, $n := .Element.GetEnum.GetNames}}{{if $i}}, {{end}}{{$n}}{{end}}`,
 	"UnknownOneOfValue":             `unsupported value {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
-	"InvalidTimeFormat":             `invalid timestamp string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidTimeFormat":             `invalid timestamp string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}; use one of layouts {{range $i, $l := .Accepted}}{{if $i}}, {{end}}"{{$l}}"{{end}}, relative time such as now, today, yesterday, -2h, 3 days ago, in 2 weeks or Unix time in seconds`,
 	"InvalidIPFormat":               `invalid IP string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidCIDRFormat":             `invalid CIDR string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidURLFormat":              `invalid URL {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}; URL must be absolute, i.e. https://example.com`,
+	"InvalidRegexpFormat":           `invalid regular expression {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidSemverFormat":           `invalid semantic version {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"PathNotFound":                  `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} does not exist`,
+	"PathAlreadyExists":             `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} already exists`,
+	"PathNotAccessible":             `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} cannot be accessed`,
+	"PathNotDir":                    `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} is not a directory`,
+	"PathNotRegular":                `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} is not a regular file`,
+	"PathNotReadable":               `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} is not readable`,
+	"PathNotWritable":               `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} is not writable`,
+	"PathExtensionNotAllowed":       `{{.Element.GetType}} {{.Element.GetPlaceholder}}: path {{.Extra}} must have one of extensions {{range $i, $e := .Element.GetExtensions}}{{if $i}}, {{end}}{{$e}}{{end}}`,
+	"PathMultipleMatches":           `{{.Element.GetType}} {{.Element.GetPlaceholder}}: pattern {{.Extra}} matches more than one path`,
+	"PathInvalidPattern":            `{{.Element.GetType}} {{.Element.GetPlaceholder}}: invalid pattern {{.Extra}}`,
+	"AtFileReadFailed":              `cannot read file {{.Extra}} with value of {{.Element.GetType}} {{.Element.GetName}}`,
 	"InvalidIntFormat":              `invalid int string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
 	"InvalidHexFormat":              `invalid hex string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
 	"InvalidBinaryFormat":           `invalid binary string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidMapEntryFormat":         `invalid entry {{.Extra}} for {{.Element.GetType}} {{.Element.GetName}}, expected key=value`,
+	"InvalidUintFormat":             `invalid unsigned int string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidFloatFormat":            `invalid float string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"InvalidBytesFormat":            `invalid size string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}; use number with optional unit, i.e. 512KB or 10MiB`,
+	"InvalidPercentFormat":          `invalid percent string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"ValueBelowMin":                 `value {{.Extra}} of {{.Element.GetType}} {{.Element.GetPlaceholder}} is less than minimum {{.Element.GetMin}}`,
+	"ValueAboveMax":                 `value {{.Extra}} of {{.Element.GetType}} {{.Element.GetPlaceholder}} is greater than maximum {{.Element.GetMax}}`,
 	"InvalidOctalFormat":            `invalid octal string {{.Extra}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"EnvVarValidationFailed":        `invalid value {{.Extra}} in environment variable {{.Key}} for {{.Element.GetType}} {{.Element.GetPlaceholder}}`,
+	"ConfigFileParseFailed":         `cannot read config file {{.Source}}: {{.Extra}}`,
+	"ConfigFileUnknownFormat":       `unsupported format of config file {{.Source}}; use yaml, json or toml`,
+	"ConfigFileValidationFailed":    `invalid value {{.Extra}} for key {{.Key}} in config file {{.Source}}`,
 	"MissingRequiredFlag":           `required {{.GetType}} --{{.Name}}{{if .Short}}(-{{.Short|Rune}}){{end}} is missing `,
 	"MissingRequiredArg":            `required {{.GetType}} {{.GetPlaceholder}} is missing `,
 	"FlagsArgsFromMultipleGroups":   `either {{.Name}} or {{.Extra}} can be specified, but not both`,
 	"NoUniqueFlagArgCommandInGroup": `must specify flag, argument or command. Try --help`,
-	"FlagValidationFailed":          `Invalid flag value {{.Extra}} for flag --{{.Element.Name}}{{if .Element.Short}}(-{{.Element.Short|Rune}}{{end}})`,
+	"FlagValidationFailed":          `Invalid flag value {{.Extra}} for flag --{{.Element.Name}}{{if .Element.Short}}(-{{.Element.Short|Rune}}){{end}}`,
+	"ConstraintRequires":            `{{.Name}} requires {{range $i, $n := .Names}}{{if $i}}, {{end}}{{$n}}{{end}}`,
+	"ConstraintConflicts":           `{{.Name}} cannot be used together with {{range $i, $n := .Names}}{{if $i}}, {{end}}{{$n}}{{end}}`,
+	"ConstraintAtLeastOneOf":        `at least one of {{range $i, $n := .Names}}{{if $i}}, {{end}}{{$n}}{{end}} must be specified`,
+	"ConstraintAllOrNone":           `{{.Name}} must be used together with {{range $i, $n := .Names}}{{if $i}}, {{end}}{{$n}}{{end}}`,
+	"ConstraintRequiredIf":          `{{.Name}} is required when {{index .Names 0}} is {{.Value}}`,
+	"ConstraintUnknownName":         `constraints of command {{.Name}} refer to unknown flag or argument {{index .Names 0}}`,
+	"ValidationErrorsTemplate":      `{{len .}} validation error{{if gt (len .) 1}}s{{end}}:`,
+	"ValidationErrorsItem":          "  - %s",
+	"PromptFailed":                  `cannot read value of {{.Element.GetType}} {{.Element.GetPlaceholder}}: {{.Extra}}`,
+	"ShellCompletionUnknownShell":   `completion script for shell {{.Extra}} is not available`,
+	"ShellCompletionInstallFailed":  `cannot write completion script to {{.Name}}: {{.Extra}}`,
+	"SecretFileReadFailed":          `cannot read value of {{.Element.GetType}} --{{.Element.GetName}} from file {{.Extra}}`,
 	"CommandRequired":               `Command required. Try --help`,
 	"command":                       `command`,
 	"subCommand":                    `sub-command`,
@@ -174,9 +370,22 @@ Use "{{.AppName}} options" for a list of global command-line options (applies to
 	"FormatMisCommandsCategory":     "Miscellaneous Commands",
 	"FormatFlagWithShort":           "-%c, --%s",
 	"FormatFlagNoShort":             "--%s",
+	"FormatNegatableFlag":           "[no-]%s",
 	"FormatFlagShort":               "-%c",
 	"FormatArg":                     "%s",
 	"FormatDefault":                 "(Default: %s)",
+	"FormatEnvVars":                 "(env: %s)",
+	"FormatRange":                   "(Range: %s..%s)",
+	"FormatMin":                     "(Min: %s)",
+	"FormatMax":                     "(Max: %s)",
+	"FormatRepeatable":              "(repeatable)",
+	"FormatEnumValue":               "Value",
+	"FormatEnumDescription":         "Description",
+	"FormatConstraintRequires":      "%s requires %s",
+	"FormatConstraintConflicts":     "%s cannot be used together with %s",
+	"FormatConstraintAtLeastOneOf":  "at least one of %s is required",
+	"FormatConstraintAllOrNone":     "%s must be used together",
+	"FormatConstraintRequiredIf":    "%s is required when %s is %s",
 	"FormatHints":                   "One of %s",
 	"FormatGlobal":                  "Global",
 }
@@ -0,0 +1,320 @@
+package gocli
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builds flag or argument bound to struct field
+type structFieldType struct {
+	flag func(field reflect.Value, tag reflect.StructTag) (IFlag, error)
+	arg  func(field reflect.Value, tag reflect.StructTag) (IArg, error)
+}
+
+func structFieldTypeOf[T TArg]() structFieldType {
+	return structFieldType{flag: newStructFlag[T], arg: newStructArg[T]}
+}
+
+// struct field types that can be bound to flags and arguments and gocli types they are parsed with
+var structFieldTypes = map[reflect.Type]structFieldType{
+	// Go types
+	reflect.TypeOf(""):                  structFieldTypeOf[String](),
+	reflect.TypeOf([]string{}):          structFieldTypeOf[[]String](),
+	reflect.TypeOf(true):                {flag: newStructFlag[Bool]},
+	reflect.TypeOf(0):                   structFieldTypeOf[Int](),
+	reflect.TypeOf([]int{}):             structFieldTypeOf[[]Int](),
+	reflect.TypeOf(uint(0)):             structFieldTypeOf[Uint](),
+	reflect.TypeOf([]uint{}):            structFieldTypeOf[[]Uint](),
+	reflect.TypeOf(float64(0)):          structFieldTypeOf[Float](),
+	reflect.TypeOf([]float64{}):         structFieldTypeOf[[]Float](),
+	reflect.TypeOf(time.Duration(0)):    structFieldTypeOf[Duration](),
+	reflect.TypeOf([]time.Duration{}):   structFieldTypeOf[[]Duration](),
+	reflect.TypeOf(time.Time{}):         structFieldTypeOf[TimeStamp](),
+	reflect.TypeOf([]time.Time{}):       structFieldTypeOf[[]TimeStamp](),
+	reflect.TypeOf(net.IP{}):            structFieldTypeOf[IP](),
+	reflect.TypeOf([]net.IP{}):          structFieldTypeOf[[]IP](),
+	reflect.TypeOf(net.IPNet{}):         structFieldTypeOf[CIDR](),
+	reflect.TypeOf(map[string]string{}): structFieldTypeOf[map[String]String](),
+	reflect.TypeOf(map[string]int{}):    structFieldTypeOf[map[String]Int](),
+	// gocli types
+	reflect.TypeOf(String("")):          structFieldTypeOf[String](),
+	reflect.TypeOf([]String{}):          structFieldTypeOf[[]String](),
+	reflect.TypeOf(Bool(false)):         {flag: newStructFlag[Bool]},
+	reflect.TypeOf(Counter(0)):          {flag: newStructFlag[Counter]},
+	reflect.TypeOf(OneOf("")):           structFieldTypeOf[OneOf](),
+	reflect.TypeOf(Email("")):           structFieldTypeOf[Email](),
+	reflect.TypeOf([]Email{}):           structFieldTypeOf[[]Email](),
+	reflect.TypeOf(File("")):            structFieldTypeOf[File](),
+	reflect.TypeOf([]File{}):            structFieldTypeOf[[]File](),
+	reflect.TypeOf(Dir("")):             structFieldTypeOf[Dir](),
+	reflect.TypeOf([]Dir{}):             structFieldTypeOf[[]Dir](),
+	reflect.TypeOf(Path("")):            structFieldTypeOf[Path](),
+	reflect.TypeOf([]Path{}):            structFieldTypeOf[[]Path](),
+	reflect.TypeOf(InputFile{}):         structFieldTypeOf[InputFile](),
+	reflect.TypeOf([]InputFile{}):       structFieldTypeOf[[]InputFile](),
+	reflect.TypeOf(OutputFile{}):        structFieldTypeOf[OutputFile](),
+	reflect.TypeOf([]OutputFile{}):      structFieldTypeOf[[]OutputFile](),
+	reflect.TypeOf(Int(0)):              structFieldTypeOf[Int](),
+	reflect.TypeOf([]Int{}):             structFieldTypeOf[[]Int](),
+	reflect.TypeOf(Hex(0)):              structFieldTypeOf[Hex](),
+	reflect.TypeOf([]Hex{}):             structFieldTypeOf[[]Hex](),
+	reflect.TypeOf(Octal(0)):            structFieldTypeOf[Octal](),
+	reflect.TypeOf([]Octal{}):           structFieldTypeOf[[]Octal](),
+	reflect.TypeOf(Binary(0)):           structFieldTypeOf[Binary](),
+	reflect.TypeOf([]Binary{}):          structFieldTypeOf[[]Binary](),
+	reflect.TypeOf(Uint(0)):             structFieldTypeOf[Uint](),
+	reflect.TypeOf([]Uint{}):            structFieldTypeOf[[]Uint](),
+	reflect.TypeOf(Float(0)):            structFieldTypeOf[Float](),
+	reflect.TypeOf([]Float{}):           structFieldTypeOf[[]Float](),
+	reflect.TypeOf(Bytes(0)):            structFieldTypeOf[Bytes](),
+	reflect.TypeOf([]Bytes{}):           structFieldTypeOf[[]Bytes](),
+	reflect.TypeOf(Percent(0)):          structFieldTypeOf[Percent](),
+	reflect.TypeOf([]Percent{}):         structFieldTypeOf[[]Percent](),
+	reflect.TypeOf(TimeStamp{}):         structFieldTypeOf[TimeStamp](),
+	reflect.TypeOf([]TimeStamp{}):       structFieldTypeOf[[]TimeStamp](),
+	reflect.TypeOf(Duration(0)):         structFieldTypeOf[Duration](),
+	reflect.TypeOf([]Duration{}):        structFieldTypeOf[[]Duration](),
+	reflect.TypeOf(IP{}):                structFieldTypeOf[IP](),
+	reflect.TypeOf([]IP{}):              structFieldTypeOf[[]IP](),
+	reflect.TypeOf(CIDR{}):              structFieldTypeOf[CIDR](),
+	reflect.TypeOf([]CIDR{}):            structFieldTypeOf[[]CIDR](),
+	reflect.TypeOf(URL{}):               structFieldTypeOf[URL](),
+	reflect.TypeOf([]URL{}):             structFieldTypeOf[[]URL](),
+	reflect.TypeOf(Regexp{}):            structFieldTypeOf[Regexp](),
+	reflect.TypeOf([]Regexp{}):          structFieldTypeOf[[]Regexp](),
+	reflect.TypeOf(Semver{}):            structFieldTypeOf[Semver](),
+	reflect.TypeOf([]Semver{}):          structFieldTypeOf[[]Semver](),
+	reflect.TypeOf(map[String]String{}): structFieldTypeOf[map[String]String](),
+	reflect.TypeOf(map[String]Int{}):    structFieldTypeOf[map[String]Int](),
+}
+
+// FromStruct builds command from struct pointed by opts. Every field with flag or arg tag becomes a flag or a positional argument
+// bound to that field as Destination, so struct is filled after command line is parsed.
+// Nested struct fields become sub-commands.
+//
+// Supported field tags:
+//
+//	flag:"name"          - field is a flag
+//	arg:"name"           - field is a positional argument, arguments are positioned in the order of fields
+//	cmd:"name"           - name of sub-command for nested struct, lower case field name is used by default
+//	short:"n"            - short flag
+//	env:"A,B"            - environment variables
+//	default:"1"          - default value
+//	min:"1", max:"10"    - range of numeric value
+//	required:"true"      - flag or argument is required
+//	group:"a,b"          - validation groups
+//	usage:"text"         - usage of flag or argument, description of sub-command
+//	placeholder:"NAME"   - placeholder
+//	hints:"a,b"          - hints
+//	hidden:"true"        - hidden flag, argument or sub-command
+//	alias:"a,b"          - sub-command aliases
+//	optional:"true"      - optional sub-command
+//
+// Command is named after struct type in lower case
+func FromStruct(opts interface{}) (Command, error) {
+	rv := reflect.ValueOf(opts)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return Command{}, fmt.Errorf("gocli: FromStruct expects pointer to struct, got %T", opts)
+	}
+	return commandFromStruct(strings.ToLower(rv.Elem().Type().Name()), rv.Elem())
+}
+
+func commandFromStruct(name string, sv reflect.Value) (Command, error) {
+	cmd := Command{
+		Name:     name,
+		Flags:    make([]IFlag, 0),
+		Args:     make([]IArg, 0),
+		Commands: make([]*Command, 0),
+	}
+
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		field := sv.Field(i)
+		tag := sf.Tag
+
+		_, is_flag := tag.Lookup("flag")
+		_, is_arg := tag.Lookup("arg")
+
+		if !is_flag && !is_arg {
+			if sf.Type.Kind() != reflect.Struct || sf.Type == reflect.TypeOf(time.Time{}) {
+				// not bound to command line
+				continue
+			}
+			sub_name := tag.Get("cmd")
+			if sub_name == "-" {
+				continue
+			}
+			if sub_name == "" {
+				sub_name = strings.ToLower(sf.Name)
+			}
+			sub_cmd, err := commandFromStruct(sub_name, field)
+			if err != nil {
+				return Command{}, err
+			}
+			sub_cmd.Description = tag.Get("usage")
+			sub_cmd.Alias = splitTagList(tag.Get("alias"))
+			sub_cmd.ValidationGroups = splitTagList(tag.Get("group"))
+			sub_cmd.Optional = tagBool(tag, "optional")
+			sub_cmd.Hidden = tagBool(tag, "hidden")
+			cmd.Commands = append(cmd.Commands, &sub_cmd)
+			continue
+		}
+
+		ft, ok := structFieldTypes[sf.Type]
+		if !ok {
+			return Command{}, fmt.Errorf("gocli: unsupported type %s of field %s.%s", sf.Type, st.Name(), sf.Name)
+		}
+		if is_flag {
+			if tag.Get("flag") == "-" {
+				continue
+			}
+			flag, err := ft.flag(field, tag)
+			if err != nil {
+				return Command{}, err
+			}
+			cmd.Flags = append(cmd.Flags, flag)
+		} else {
+			if ft.arg == nil {
+				return Command{}, fmt.Errorf("gocli: type %s of field %s.%s cannot be used for argument", sf.Type, st.Name(), sf.Name)
+			}
+			if tag.Get("arg") == "-" {
+				continue
+			}
+			arg, err := ft.arg(field, tag)
+			if err != nil {
+				return Command{}, err
+			}
+			cmd.Args = append(cmd.Args, arg)
+		}
+	}
+	return cmd, nil
+}
+
+func newStructFlag[T TFlag](field reflect.Value, tag reflect.StructTag) (IFlag, error) {
+	if err := checkFieldType[T](field); err != nil {
+		return nil, err
+	}
+	var short rune
+	if s := []rune(tag.Get("short")); len(s) > 0 {
+		short = s[0]
+	}
+	return &Flag[T]{
+		Name:             tag.Get("flag"),
+		Short:            short,
+		Usage:            tag.Get("usage"),
+		Default:          tag.Get("default"),
+		Hints:            splitTagList(tag.Get("hints")),
+		Required:         tagBool(tag, "required"),
+		Placeholder:      tag.Get("placeholder"),
+		ValidationGroups: splitTagList(tag.Get("group")),
+		Hidden:           tagBool(tag, "hidden"),
+		EnvVars:          splitTagList(tag.Get("env")),
+		Min:              tag.Get("min"),
+		Max:              tag.Get("max"),
+		Secret:           tagBool(tag, "secret"),
+		structField:      field,
+	}, nil
+}
+
+func newStructArg[T TArg](field reflect.Value, tag reflect.StructTag) (IArg, error) {
+	if err := checkFieldType[T](field); err != nil {
+		return nil, err
+	}
+	return &Arg[T]{
+		Name:             tag.Get("arg"),
+		Usage:            tag.Get("usage"),
+		Default:          tag.Get("default"),
+		Hints:            splitTagList(tag.Get("hints")),
+		Required:         tagBool(tag, "required"),
+		Placeholder:      tag.Get("placeholder"),
+		ValidationGroups: splitTagList(tag.Get("group")),
+		Hidden:           tagBool(tag, "hidden"),
+		EnvVars:          splitTagList(tag.Get("env")),
+		Min:              tag.Get("min"),
+		Max:              tag.Get("max"),
+		Secret:           tagBool(tag, "secret"),
+		structField:      field,
+	}, nil
+}
+
+// value of type T is copied to struct field when set, so field must have the same underlying type as T
+// or be a slice or map of elements with the same underlying types, i.e. []string for []String
+func checkFieldType[T any](field reflect.Value) error {
+	value_type := reflect.TypeOf((*T)(nil)).Elem()
+	if !sameUnderlying(field.Type(), value_type) {
+		return fmt.Errorf("gocli: type %s of field cannot be bound to %s", field.Type(), value_type)
+	}
+	return nil
+}
+
+// reports whether types have the same underlying type or are slices or maps of such types
+func sameUnderlying(a reflect.Type, b reflect.Type) bool {
+	if reflect.PtrTo(a).ConvertibleTo(reflect.PtrTo(b)) {
+		return true
+	}
+	if a.Kind() != b.Kind() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Slice:
+		return sameUnderlying(a.Elem(), b.Elem())
+	case reflect.Map:
+		return sameUnderlying(a.Key(), b.Key()) && sameUnderlying(a.Elem(), b.Elem())
+	}
+	return false
+}
+
+// copies src to dst of type with the same underlying type, slices and maps are copied element by element
+func copyToField(dst reflect.Value, src reflect.Value) {
+	if reflect.PtrTo(src.Type()).ConvertibleTo(reflect.PtrTo(dst.Type())) {
+		dst.Set(src.Convert(dst.Type()))
+		return
+	}
+	switch src.Kind() {
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		values := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyToField(values.Index(i), src.Index(i))
+		}
+		dst.Set(values)
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+		values := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			key := reflect.New(dst.Type().Key()).Elem()
+			copyToField(key, iter.Key())
+			value := reflect.New(dst.Type().Elem()).Elem()
+			copyToField(value, iter.Value())
+			values.SetMapIndex(key, value)
+		}
+		dst.Set(values)
+	}
+}
+
+func splitTagList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func tagBool(tag reflect.StructTag, key string) bool {
+	b, _ := strconv.ParseBool(tag.Get(key))
+	return b
+}
@@ -0,0 +1,141 @@
+package gocli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type deployOptions struct {
+	Region  string        `flag:"region" short:"r" usage:"region to deploy to" required:"true"`
+	Replica int           `flag:"replicas" default:"1"`
+	Labels  []string      `flag:"label" short:"l"`
+	Timeout time.Duration `flag:"timeout" default:"30s"`
+	Force   bool          `flag:"force" short:"f"`
+	Target  string        `arg:"target" required:"true"`
+	ignored string
+}
+
+type structOptions struct {
+	Verbose bool          `flag:"verbose" short:"v"`
+	Output  string        `flag:"output" hints:"json,table" default:"table"`
+	Deploy  deployOptions `cmd:"deploy" usage:"deploy application" alias:"dep"`
+	Status  struct {
+		Watch bool `flag:"watch"`
+	} `usage:"show status"`
+}
+
+func TestFromStruct(t *testing.T) {
+
+	opts := structOptions{}
+	cmd, err := FromStruct(&opts)
+	if err != nil {
+		t.Fatalf("FromStruct() error = %v", err)
+	}
+	if cmd.Name != "structoptions" || len(cmd.Flags) != 2 || len(cmd.Commands) != 2 {
+		t.Fatalf("FromStruct() built unexpected command %+v", cmd)
+	}
+	if cmd.Commands[1].Name != "status" || cmd.Commands[0].Alias[0] != "dep" {
+		t.Errorf("FromStruct() built unexpected sub-commands")
+	}
+
+	app := New()
+	app.AddFlags(cmd.Flags)
+	app.Commands = append(app.Commands, cmd.Commands...)
+	app.SetWriter(bytes.NewBuffer(nil))
+	app.SetErrorWriter(bytes.NewBuffer(nil))
+
+	// run twice to make sure struct stays bound after flags are cleared
+	for i := 0; i < 2; i++ {
+		err = app.Run([]string{"test", "-v", "dep", "-r", "us-east", "-l", "a", "--label=b", "-f", "web"})
+		if err != nil {
+			t.Fatalf("Application.Run() error = %v", err)
+		}
+		expected := structOptions{
+			Verbose: true,
+			Output:  "table",
+			Deploy: deployOptions{
+				Region:  "us-east",
+				Replica: 1,
+				Labels:  []string{"a", "b"},
+				Timeout: 30 * time.Second,
+				Force:   true,
+				Target:  "web",
+			},
+		}
+		if !reflect.DeepEqual(opts, expected) {
+			t.Errorf("struct was not filled, got %+v, want %+v", opts, expected)
+		}
+	}
+
+	// missing required flag bound to struct field
+	if err = app.Run([]string{"test", "deploy", "web"}); err == nil {
+		t.Errorf("Application.Run() expected missing required flag error")
+	}
+
+	if _, err = FromStruct(opts); err == nil {
+		t.Errorf("FromStruct() expected error for non pointer")
+	}
+	bad := struct {
+		Value complex64 `flag:"value"`
+	}{}
+	if _, err = FromStruct(&bad); err == nil {
+		t.Errorf("FromStruct() expected error for unsupported type")
+	}
+}
+
+func TestFieldType(t *testing.T) {
+
+	opts := struct {
+		Name   string
+		Labels []string
+		Env    map[string]string
+		Count  int
+	}{}
+	v := reflect.ValueOf(&opts).Elem()
+
+	if err := checkFieldType[String](v.Field(0)); err != nil {
+		t.Errorf("checkFieldType() error = %v", err)
+	}
+	if err := checkFieldType[[]String](v.Field(1)); err != nil {
+		t.Errorf("checkFieldType() error = %v", err)
+	}
+	if err := checkFieldType[map[String]String](v.Field(2)); err != nil {
+		t.Errorf("checkFieldType() error = %v", err)
+	}
+	// different underlying types are rejected
+	if err := checkFieldType[String](v.Field(3)); err == nil {
+		t.Errorf("checkFieldType() expected error for int field bound to String")
+	}
+	if err := checkFieldType[[]Int](v.Field(1)); err == nil {
+		t.Errorf("checkFieldType() expected error for []string field bound to []Int")
+	}
+
+	// values are copied element by element
+	copyToField(v.Field(0), reflect.ValueOf(String("a")))
+	copyToField(v.Field(1), reflect.ValueOf([]String{"b", "c"}))
+	copyToField(v.Field(2), reflect.ValueOf(map[String]String{"d": "e"}))
+	if opts.Name != "a" || !reflect.DeepEqual(opts.Labels, []string{"b", "c"}) || !reflect.DeepEqual(opts.Env, map[string]string{"d": "e"}) {
+		t.Errorf("copyToField() copied %+v", opts)
+	}
+}
+
+func TestDestinationKeptByClear(t *testing.T) {
+
+	// value of destination provided by user is not reset between runs
+	name := "preset"
+	app := New()
+	app.AddFlag(&Flag[String]{Name: "name", Destination: (*String)(&name)})
+	app.AddCommand(Command{Name: "run"})
+	app.SetWriter(bytes.NewBuffer(nil))
+	app.SetErrorWriter(bytes.NewBuffer(nil))
+	for i := 0; i < 2; i++ {
+		if err := app.Run([]string{"test", "run"}); err != nil {
+			t.Fatalf("Application.Run() error = %v", err)
+		}
+		if name != "preset" {
+			t.Errorf("destination = %q, want preset", name)
+		}
+	}
+}
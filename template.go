@@ -15,8 +15,6 @@ import (
 	"golang.org/x/text/language"
 )
 
-var templateManager *TemplateManager
-
 type OutputFormat string
 
 const (
@@ -40,15 +38,44 @@ type TemplateManager struct {
 }
 
 type TokenTemplateContext struct {
-	Name  string
-	Extra string
+	Name        string
+	Extra       string
+	Suggestions []string // "did you mean" candidates
 }
 
 type ElementTemplateContext struct {
+	Element     IValidatable
+	Extra       string
+	Suggestions []string // "did you mean" candidates
+	Accepted    []string // accepted formats of value
+}
+
+type SourceTemplateContext struct {
 	Element IValidatable
+	Source  string // where value came from, i.e. file name
+	Key     string // environment variable or key within the source
 	Extra   string
 }
 
+type ConstraintTemplateContext struct {
+	Name  string   // element the rule is declared for
+	Names []string // other elements of the rule
+	Value string   // value that makes element required
+}
+
+type PromptTemplateContext struct {
+	Element IFlagArg
+	Name    string         // --name for flag, placeholder for argument
+	Default string         // value used if answer is empty
+	Options []PromptOption // choices of selection menu
+}
+
+type PromptOption struct {
+	Number      int
+	Value       string
+	Description string // description of enum value
+}
+
 type UsageTemplateContext struct {
 	AppName           string
 	CurrentCommand    Command
@@ -59,36 +86,45 @@ type UsageTemplateContext struct {
 	DocGeneration     bool
 }
 
-func initTemplateManager() {
+// creates template manager with default language and strings. Every application owns its template manager
+func newTemplateManager() *TemplateManager {
 
 	default_lang := language.MustParse("en_us")
-	templateManager = &TemplateManager{
-		CustomFuncs: template.FuncMap{
-			"Translate":             tplTranslate,
-			"Dict":                  tplDict,
-			"HLevel":                tplHeaderLevel,
-			"BlockBracket":          tplBlockBracket,
-			"ToUpper":               strings.ToUpper,
-			"ToLower":               strings.ToLower,
-			"Rune":                  tplRune,
-			"IsFlag":                tplIsFlag,
-			"IsArg":                 tplIsArg,
-			"Synopsis":              tplSynopsys,
-			"SynopsisFlag":          tplSynopsisFlag,
-			"DefinitionList":        tplDefinitionList,
-			"FlagsArgsToTwoColumns": tplFlagsArgsToTwoColumns,
-			"CommandCategories":     tplCommandCategories,
-			"CommandsToTwoColumns":  tplCommandsToTwoColumns,
-			"FormatTemplate":        tplFormatTemplate,
-		},
+	t := &TemplateManager{
 		localizer:    i18n.NewLocalizer(default_lang, default_lang),
 		outputFormat: TemplateTerminal,
 		css:          "",
 		icon:         "",
 		TOC:          false,
 	}
+	t.CustomFuncs = template.FuncMap{
+		"Translate":             t.tplTranslate,
+		"Dict":                  tplDict,
+		"HLevel":                t.tplHeaderLevel,
+		"BlockBracket":          tplBlockBracket,
+		"ToUpper":               strings.ToUpper,
+		"ToLower":               strings.ToLower,
+		"Rune":                  tplRune,
+		"IsFlag":                tplIsFlag,
+		"IsArg":                 tplIsArg,
+		"Synopsis":              t.tplSynopsys,
+		"Constraints":           t.tplConstraints,
+		"SynopsisFlag":          tplSynopsisFlag,
+		"DefinitionList":        tplDefinitionList,
+		"FlagsArgsToTwoColumns": t.tplFlagsArgsToTwoColumns,
+		"CommandCategories":     t.tplCommandCategories,
+		"CommandsToTwoColumns":  t.tplCommandsToTwoColumns,
+		"FormatTemplate":        t.tplFormatTemplate,
+	}
+
+	// localizer keeps and updates entries of default language, so give it its own copy
+	entries := make(i18n.Entries, len(GoCliStrings))
+	for key, msg := range GoCliStrings {
+		entries[key] = msg
+	}
+	t.localizer.AddUpdateTranslation(default_lang, entries)
 
-	templateManager.localizer.AddUpdateTranslation(default_lang, GoCliStrings)
+	return t
 }
 
 func (t TemplateManager) AddTranslation(lang language.Tag, entries i18n.Entries) {
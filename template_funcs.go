@@ -12,12 +12,12 @@ import (
 )
 
 // generic template functions
-func tplTranslate(in string) string {
-	return templateManager.localizer.Sprintf(in)
+func (t *TemplateManager) tplTranslate(in string) string {
+	return t.localizer.Sprintf(in)
 }
 
-func tplHeaderLevel(level int) string {
-	return strings.Repeat("#", level+templateManager.currentLevel)
+func (t *TemplateManager) tplHeaderLevel(level int) string {
+	return strings.Repeat("#", level+t.currentLevel)
 }
 
 func tplBlockBracket() string {
@@ -58,11 +58,15 @@ func tplSynopsisFlag(flag IFlag) string {
 	if flag.GetShort() != 0 {
 		flag_str = fmt.Sprintf("-%c ", flag.GetShort())
 	}
-	flag_str += fmt.Sprintf("--%s", flag.GetName())
+	if flag.IsNegatable() {
+		flag_str += fmt.Sprintf("--[no-]%s", flag.GetName())
+	} else {
+		flag_str += fmt.Sprintf("--%s", flag.GetName())
+	}
 	return flag_str
 }
 
-func tplSynopsys(ctx UsageTemplateContext) string {
+func (t *TemplateManager) tplSynopsys(ctx UsageTemplateContext) string {
 	synopsis := ctx.CurrentCommand.Name
 	for p := ctx.CurrentCommand.parent; p != nil; p = p.parent {
 		parent_synopsis := p.Name
@@ -76,10 +80,10 @@ func tplSynopsys(ctx UsageTemplateContext) string {
 			} else {
 				has_optional_flags = true
 			}
-			
+
 		}
 		if has_optional_flags {
-			parent_synopsis += tplTranslate("options")
+			parent_synopsis += t.tplTranslate("options")
 		}
 		synopsis = parent_synopsis + synopsis
 	}
@@ -87,7 +91,7 @@ func tplSynopsys(ctx UsageTemplateContext) string {
 	return synopsis
 }
 
-func tplFlagsArgsToTwoColumns(flags_args []IFlagArg, level int) [][2]string {
+func (t *TemplateManager) tplFlagsArgsToTwoColumns(flags_args []IFlagArg, level int) [][2]string {
 	rows := [][2]string{}
 	var name string
 
@@ -96,34 +100,64 @@ func tplFlagsArgsToTwoColumns(flags_args []IFlagArg, level int) [][2]string {
 
 	for _, fa := range flags_args {
 		if f, ok := fa.(IFlag); ok {
+			flag_name := f.GetName()
+			if f.IsNegatable() {
+				flag_name = t.localizer.Sprintf("FormatNegatableFlag", flag_name)
+			}
 			if f.GetShort() != 0 {
-				name = templateManager.localizer.Sprintf("FormatFlagWithShort", f.GetShort(), f.GetName())
+				name = t.localizer.Sprintf("FormatFlagWithShort", f.GetShort(), flag_name)
 			} else {
-				name = templateManager.localizer.Sprintf("FormatFlagNoShort", f.GetName())
+				name = t.localizer.Sprintf("FormatFlagNoShort", flag_name)
 			}
 		} else {
-			name = templateManager.localizer.Sprintf("FormatArg", fa.GetName())
+			name = t.localizer.Sprintf("FormatArg", fa.GetName())
 		}
 
 		// usage can be a template - so make it first
 		buf := bytes.NewBuffer(nil)
-		templateManager.doFormatTemplate(buf, fa.GetUsage(), fa)
+		t.doFormatTemplate(buf, fa.GetUsage(), fa)
 		usage := buf.String()
 		usage = strings.TrimRight(usage, " \t.")
-		if len(fa.GetHints()) > 0 {
-			usage += " " + templateManager.localizer.Sprintf("FormatHints", strings.Join(fa.GetHints(), ","))
+		if f, ok := fa.(IFlag); ok && f.IsCounter() {
+			usage += " " + t.localizer.Sprintf("FormatRepeatable")
+		}
+		if len(fa.GetHints()) > 0 && fa.GetEnum() == nil {
+			usage += " " + t.localizer.Sprintf("FormatHints", strings.Join(fa.GetHints(), ","))
+		}
+		switch {
+		case fa.GetMin() != "" && fa.GetMax() != "":
+			usage += " " + t.localizer.Sprintf("FormatRange", fa.GetMin(), fa.GetMax())
+		case fa.GetMin() != "":
+			usage += " " + t.localizer.Sprintf("FormatMin", fa.GetMin())
+		case fa.GetMax() != "":
+			usage += " " + t.localizer.Sprintf("FormatMax", fa.GetMax())
 		}
 		if fa.GetDefault() != "" {
-			usage += " " + templateManager.localizer.Sprintf("FormatDefault", fa.GetDefault())
+			usage += " " + t.localizer.Sprintf("FormatDefault", redactValue(fa, fa.GetDefault()))
+		}
+		if len(fa.GetEnvVars()) > 0 {
+			usage += " " + t.localizer.Sprintf("FormatEnvVars", strings.Join(fa.GetEnvVars(), ", "))
+		}
+		if fa.GetEnum() != nil {
+			usage += t.enumTable(fa.GetEnum())
 		}
 		rows = append(rows, [2]string{name, usage})
 	}
 	return rows
 }
-func tplCommandCategories(commands []*Command) []*CommandCategory {
+
+// allowed values of enum with descriptions as table; table is indented so it stays part of flag or argument usage
+func (t *TemplateManager) enumTable(enum IEnum) string {
+	table := fmt.Sprintf("\n\n    | %s | %s |\n    |---|---|\n", t.localizer.Sprintf("FormatEnumValue"), t.localizer.Sprintf("FormatEnumDescription"))
+	for _, name := range enum.GetNames() {
+		table += fmt.Sprintf("    | %s | %s |\n", name, enum.GetDescription(name))
+	}
+	return table
+}
+func (t *TemplateManager) tplCommandCategories(commands []*Command) []*CommandCategory {
 	categories := make([]*CommandCategory, 0)
 
-	misc_cat := CommandCategory{Name: templateManager.localizer.Sprintf("FormatMisCommandsCategory"), Order: 99, commands: make([]*Command, 0)}
+	misc_cat := CommandCategory{Name: t.localizer.Sprintf("FormatMisCommandsCategory"), Order: 99, commands: make([]*Command, 0)}
 	categories = append(categories, &misc_cat)
 
 	for _, cmd := range commands {
@@ -161,13 +195,13 @@ func tplCommandCategories(commands []*Command) []*CommandCategory {
 	if len(categories) == 1 {
 		// we only have one category, it is is unnamed by user, name it Commands
 		if categories[0] == &misc_cat {
-			misc_cat.Name = templateManager.localizer.Sprintf("FormatCommandsCategory")
+			misc_cat.Name = t.localizer.Sprintf("FormatCommandsCategory")
 		}
 	}
 
 	return categories
 }
-func tplCommandsToTwoColumns(commands []*Command) [][2]string {
+func (t *TemplateManager) tplCommandsToTwoColumns(commands []*Command) [][2]string {
 
 	rows := [][2]string{}
 	for _, cmd := range commands {
@@ -177,7 +211,7 @@ func tplCommandsToTwoColumns(commands []*Command) [][2]string {
 			name = name + "(" + aliases + ")"
 		}
 
-		usage := tplFormatTemplate(cmd.Description, cmd)
+		usage := t.tplFormatTemplate(cmd.Description, cmd)
 		// take first line only
 		lines := strings.Split(usage, "\n")
 		rows = append(rows, [2]string{name, lines[0]})
@@ -185,10 +219,10 @@ func tplCommandsToTwoColumns(commands []*Command) [][2]string {
 	return rows
 }
 
-func tplFormatTemplate(tpl string, obj any) string {
+func (t *TemplateManager) tplFormatTemplate(tpl string, obj any) string {
 
 	buf := bytes.NewBuffer(nil)
-	templateManager.doFormatTemplate(buf, tpl, obj)
+	t.doFormatTemplate(buf, tpl, obj)
 	return buf.String()
 }
 
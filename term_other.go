@@ -0,0 +1,19 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package gocli
+
+import (
+	"errors"
+	"os"
+)
+
+// terminal cannot be told from other character devices on this platform
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// echo cannot be turned off on this platform, secret input is echoed
+func disableEcho(f *os.File) (func(), error) {
+	return nil, errors.New("disabling echo is not supported")
+}
@@ -0,0 +1,28 @@
+package gocli
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// true if file is a console
+func isTerminal(f *os.File) bool {
+	var mode uint32
+	return windows.GetConsoleMode(windows.Handle(f.Fd()), &mode) == nil
+}
+
+// turns off echo of console input, returned function restores previous state
+func disableEcho(f *os.File) (func(), error) {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return nil, err
+	}
+	if err := windows.SetConsoleMode(handle, mode&^windows.ENABLE_ECHO_INPUT|windows.ENABLE_PROCESSED_INPUT|windows.ENABLE_LINE_INPUT); err != nil {
+		return nil, err
+	}
+	return func() {
+		windows.SetConsoleMode(handle, mode)
+	}, nil
+}
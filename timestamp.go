@@ -0,0 +1,161 @@
+package gocli
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ez-leka/gocli/i18n"
+)
+
+type TimeStamp time.Time
+
+// layouts tried in order when flag or argument does not set its own Layouts
+var defaultTimeLayouts = []string{
+	time.RFC822,      // "02 Jan 06 15:04 MST"
+	time.RFC822Z,     // "02 Jan 06 15:04 -0700"
+	time.RFC850,      // "Monday, 02-Jan-06 15:04:05 MST"
+	time.RFC1123,     // "Mon, 02 Jan 2006 15:04:05 MST"
+	time.RFC1123Z,    // "Mon, 02 Jan 2006 15:04:05 -0700"
+	time.RFC3339,     // "2006-01-02T15:04:05Z07:00"
+	time.RFC3339Nano, // "2006-01-02T15:04:05.999999999Z07:00"
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 03:04:05 PM",
+	"01/02/2006 03:04:05PM",
+	"01/02/2006",
+	"15:04:05",
+	"15:04",
+	"03:04 PM",
+	"03:04PM",
+}
+
+// current time, replaced in tests
+var timeNow = time.Now
+
+// "3 days ago" or "in 2 weeks"
+var relativeTimeRegexp = regexp.MustCompile(`^(?:(\d+)\s*([a-z]+)\s+ago|in\s+(\d+)\s*([a-z]+))$`)
+
+var relativeTimeUnits = map[string]string{
+	"s": "second", "sec": "second", "second": "second",
+	"m": "minute", "min": "minute", "minute": "minute",
+	"h": "hour", "hr": "hour", "hour": "hour",
+	"d": "day", "day": "day",
+	"w": "week", "wk": "week", "week": "week",
+	"mo": "month", "month": "month",
+	"y": "year", "yr": "year", "year": "year",
+}
+
+func (s *TimeStamp) GetReturnType() reflect.Type {
+	return reflect.TypeOf(time.Time{})
+}
+
+// accepts layouts of flag or argument (default ones if not set), relative time such as now, yesterday, -2h or 3 days ago
+// and Unix time in seconds. Layouts are tried first, so all-digit layout such as 20060102 wins over Unix time.
+// Values without time zone are in Location of flag or argument, UTC if not set
+func (s *TimeStamp) FromString(v string, fa IFlagArg) error {
+	loc := fa.GetLocation()
+	if loc == nil {
+		loc = time.UTC
+	}
+	layouts := fa.GetLayouts()
+	if len(layouts) == 0 {
+		layouts = defaultTimeLayouts
+	}
+
+	for _, l := range layouts {
+		t, err := time.ParseInLocation(l, v, loc)
+		if err != nil {
+			continue
+		}
+		if t.Year() == 0 && t.YearDay() == 1 {
+			// layout has time only - it is today
+			now := timeNow().In(loc)
+			t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+		}
+		*s = TimeStamp(t)
+		return nil
+	}
+
+	if t, ok := parseRelativeTime(strings.ToLower(strings.TrimSpace(v)), loc); ok {
+		*s = TimeStamp(t)
+		return nil
+	}
+
+	return i18n.NewError("InvalidTimeFormat", ElementTemplateContext{Element: fa, Extra: v, Accepted: layouts})
+}
+
+func (s *TimeStamp) GetValue() interface{} {
+	return time.Time(*s)
+}
+
+func parseRelativeTime(v string, loc *time.Location) (time.Time, bool) {
+	now := timeNow().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch v {
+	case "now":
+		return now, true
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	// signed duration, i.e. -2h or +30m
+	if strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		if d, err := time.ParseDuration(v); err == nil {
+			return now.Add(d), true
+		}
+		return time.Time{}, false
+	}
+
+	// Unix time in seconds
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(secs, 0).In(loc), true
+	}
+
+	m := relativeTimeRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return time.Time{}, false
+	}
+	count, unit, sign := m[1], m[2], -1
+	if count == "" {
+		count, unit, sign = m[3], m[4], 1
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return time.Time{}, false
+	}
+	n *= sign
+	name, ok := relativeTimeUnits[unit]
+	if !ok {
+		// plural form, i.e. days
+		name, ok = relativeTimeUnits[strings.TrimSuffix(unit, "s")]
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	switch name {
+	case "second":
+		return now.Add(time.Duration(n) * time.Second), true
+	case "minute":
+		return now.Add(time.Duration(n) * time.Minute), true
+	case "hour":
+		return now.Add(time.Duration(n) * time.Hour), true
+	case "day":
+		return now.AddDate(0, 0, n), true
+	case "week":
+		return now.AddDate(0, 0, 7*n), true
+	case "month":
+		return now.AddDate(0, n, 0), true
+	default:
+		return now.AddDate(n, 0, 0), true
+	}
+}
@@ -1,11 +1,13 @@
 package gocli
 
 import (
+	"fmt"
+	"math"
 	"net"
 	"net/mail"
-	"os"
-	"path/filepath"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -13,6 +15,8 @@ import (
 	"github.com/ez-leka/gocli/i18n"
 )
 
+// ISetable is implemented by pointer to value type of flags and arguments. Implement it to use own types:
+// FromString parses command line value, GetValue returns value of GetReturnType type
 type ISetable interface {
 	FromString(string, IFlagArg) error
 	GetValue() interface{}
@@ -32,19 +36,69 @@ type IValidatable interface {
 
 type String string
 type Bool bool
+type Counter int // flag that counts its occurrences, i.e. -vvv
 type Int int
 type Hex int
 type Binary int
 type Octal int
+type Uint uint
+type Float float64
+type Bytes int64     // size in bytes, accepts units, i.e. 10MiB or 2GB
+type Percent float64 // percentage, accepts optional % sign, i.e. 50%
 type OneOf string
 type Email string
-type TimeStamp time.Time
 type Duration time.Duration
 type IP net.IP
-type File String // Represents existing file path. Will not validate if file does not exist. Use String type if you do not want validate for existance
+type CIDR net.IPNet
+type URL url.URL
+type Regexp regexp.Regexp
+
+// Semver is semantic version, i.e. 1.2.3-rc.1+build.5. Leading v is accepted on command line
+type Semver struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease string
+	Build      string
+}
+
+// ValueSource tells where the value of a flag or an argument came from
+type ValueSource int
+
+const (
+	SourceNone        ValueSource = iota // value was not set
+	SourceCommandLine                    // value was set on command line
+	SourceEnvironment                    // value was taken from environment variable
+	SourceConfigFile                     // value was taken from configuration file
+	SourceDefault                        // default value was used
+	SourcePrompt                         // value was entered by user when prompted
+)
+
+// ICompleter can be implemented by pointer to value type to offer shell completion candidates
+// with optional descriptions for flags and arguments without hints; candidates not starting with prefix are dropped
+type ICompleter interface {
+	Complete(prefix string, fa IFlagArg) []Completion
+}
+
+// Completion is a shell completion candidate; description is shown by shells that support it
+type Completion struct {
+	Value       string
+	Description string
+}
 
+// CompletionFunc offers shell completion candidates for value of flag or argument or for command, i.e. names read
+// from cache file. Candidates not starting with prefix are dropped; if error is returned nothing is offered
+type CompletionFunc func(app *Application, prefix string) ([]Completion, error)
+
+// TArgFlag lists value types of flags and arguments; user defined types are used through Value and Values,
+// which are matched by their underlying struct types
 type TArgFlag interface {
-	String | []String | OneOf | Email | []Email | File | []File | TimeStamp | []TimeStamp | Duration | []Duration | Int | []Int | Hex | []Hex | Octal | []Octal | Binary | []Binary | IP | []IP
+	String | []String | OneOf | Email | []Email | File | []File | Dir | []Dir | Path | []Path | TimeStamp | []TimeStamp | Duration | []Duration | Int | []Int | Hex | []Hex | Octal | []Octal | Binary | []Binary | IP | []IP |
+		Uint | []Uint | Float | []Float | Bytes | []Bytes | Percent | []Percent |
+		InputFile | []InputFile | OutputFile | []OutputFile |
+		URL | []URL | Regexp | []Regexp | Semver | []Semver | CIDR | []CIDR |
+		map[String]String | map[String]Int |
+		~struct{ value ISetable } | ~struct{ values []ISetable }
 }
 
 func (s *String) GetReturnType() reflect.Type {
@@ -75,6 +129,29 @@ func (s *Bool) GetValue() interface{} {
 	return bool(*s)
 }
 
+func (s *Counter) GetReturnType() reflect.Type {
+	var i int
+	return reflect.TypeOf(i)
+}
+
+// value starting with + is added to the counter, any other value replaces it
+func (s *Counter) FromString(v string, fa IFlagArg) error {
+	i, err := strconv.ParseInt(v, 10, 0)
+	if err != nil {
+		return i18n.NewError("InvalidIntFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	if strings.HasPrefix(v, "+") {
+		*s += Counter(i)
+	} else {
+		*s = Counter(i)
+	}
+	return nil
+}
+
+func (s *Counter) GetValue() interface{} {
+	return int(*s)
+}
+
 func (s *OneOf) GetReturnType() reflect.Type {
 	return reflect.TypeOf("")
 }
@@ -95,7 +172,7 @@ func (s *OneOf) FromString(v string, fa IFlagArg) error {
 		}
 	}
 
-	true_value := inHints(hints, v)
+	true_value := inHints(hints, v, fa.IsIgnoreCase())
 	if true_value == "" {
 		return i18n.NewError("UnknownOneOfValue", ElementTemplateContext{Element: fa, Extra: v})
 	}
@@ -125,63 +202,6 @@ func (s *Email) GetValue() interface{} {
 	return string(*s)
 }
 
-func (s *File) GetReturnType() reflect.Type {
-	return reflect.TypeOf("")
-}
-
-func (s *File) FromString(v string, fa IFlagArg) error {
-	matches, err := filepath.Glob(v)
-
-	if err != nil {
-		return err
-	}
-	if len(matches) == 0 {
-		return os.ErrNotExist
-	}
-	*s = File(v)
-	return nil
-}
-func (s *File) GetValue() interface{} {
-	return string(*s)
-}
-
-func (s *TimeStamp) GetReturnType() reflect.Type {
-	return reflect.TypeOf(time.Time{})
-}
-
-func (s *TimeStamp) FromString(v string, fa IFlagArg) error {
-	// try to parse it any known format
-	layouts := []string{
-		time.RFC822,      //	= "02 Jan 06 15:04 MST"
-		time.RFC822Z,     //    = "02 Jan 06 15:04 -0700" // RFC822 with numeric zone
-		time.RFC850,      //    = "Monday, 02-Jan-06 15:04:05 MST"
-		time.RFC1123,     //	= "Mon, 02 Jan 2006 15:04:05 MST"
-		time.RFC1123Z,    //	= "Mon, 02 Jan 2006 15:04:05 -0700" // RFC1123 with numeric zone
-		time.RFC3339,     //  	= "2006-01-02T15:04:05Z07:00"
-		time.RFC3339Nano, // 	= "2006-01-02T15:04:05.999999999Z07:00",
-		"01/01/2006 03:04:05 PM",
-		"01/01/2006 03:04:05PM",
-		"03:04 PM",
-		"03:04PM",
-	}
-	parsed := false
-	for _, l := range layouts {
-		t, err := time.Parse(l, v)
-		if err == nil {
-			*s = TimeStamp(t)
-			parsed = true
-			break
-		}
-	}
-	if !parsed {
-		return i18n.NewError("InvalidTimeFormat", ElementTemplateContext{Element: fa, Extra: v})
-	}
-	return nil
-}
-func (s *TimeStamp) GetValue() interface{} {
-	return time.Time(*s)
-}
-
 func (s *Duration) GetReturnType() reflect.Type {
 	var d time.Duration
 	return reflect.TypeOf(d)
@@ -218,6 +238,155 @@ func (s *IP) GetValue() interface{} {
 	return net.IP(*s)
 }
 
+func (s *CIDR) GetReturnType() reflect.Type {
+	return reflect.TypeOf(&net.IPNet{})
+}
+
+func (s *CIDR) FromString(v string, fa IFlagArg) error {
+
+	_, ipnet, err := net.ParseCIDR(v)
+	if err != nil {
+		return i18n.NewError("InvalidCIDRFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = CIDR(*ipnet)
+	return nil
+}
+
+func (s *CIDR) GetValue() interface{} {
+	return (*net.IPNet)(s)
+}
+
+func (s *URL) GetReturnType() reflect.Type {
+	return reflect.TypeOf(&url.URL{})
+}
+
+// only absolute URLs are accepted
+func (s *URL) FromString(v string, fa IFlagArg) error {
+
+	u, err := url.Parse(v)
+	if err != nil || u.Scheme == "" {
+		return i18n.NewError("InvalidURLFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = URL(*u)
+	return nil
+}
+
+func (s *URL) GetValue() interface{} {
+	return (*url.URL)(s)
+}
+
+func (s *Regexp) GetReturnType() reflect.Type {
+	return reflect.TypeOf(&regexp.Regexp{})
+}
+
+func (s *Regexp) FromString(v string, fa IFlagArg) error {
+
+	re, err := regexp.Compile(v)
+	if err != nil {
+		return i18n.NewError("InvalidRegexpFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = Regexp(*re)
+	return nil
+}
+
+func (s *Regexp) GetValue() interface{} {
+	return (*regexp.Regexp)(s)
+}
+
+// https://semver.org/#is-there-a-suggested-regular-expression-regex-to-check-a-semver-string
+var semverRegexp = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+func (s *Semver) GetReturnType() reflect.Type {
+	return reflect.TypeOf(Semver{})
+}
+
+func (s *Semver) FromString(v string, fa IFlagArg) error {
+
+	m := semverRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return i18n.NewError("InvalidSemverFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	// numbers are validated by regexp, so only overflow can fail
+	var err error
+	version := Semver{PreRelease: m[4], Build: m[5]}
+	for i, n := range []*uint64{&version.Major, &version.Minor, &version.Patch} {
+		if *n, err = strconv.ParseUint(m[i+1], 10, 64); err != nil {
+			return i18n.NewError("InvalidSemverFormat", ElementTemplateContext{Element: fa, Extra: v})
+		}
+	}
+	*s = version
+	return nil
+}
+
+func (s *Semver) GetValue() interface{} {
+	return *s
+}
+
+func (s Semver) String() string {
+	str := fmt.Sprintf("%d.%d.%d", s.Major, s.Minor, s.Patch)
+	if s.PreRelease != "" {
+		str += "-" + s.PreRelease
+	}
+	if s.Build != "" {
+		str += "+" + s.Build
+	}
+	return str
+}
+
+// Compare returns -1, 0 or 1 if version has lower, same or higher precedence than other; build metadata is ignored
+func (s Semver) Compare(other Semver) int {
+	for _, p := range [][2]uint64{{s.Major, other.Major}, {s.Minor, other.Minor}, {s.Patch, other.Patch}} {
+		if p[0] != p[1] {
+			if p[0] < p[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	// version without pre-release is higher than with one
+	switch {
+	case s.PreRelease == other.PreRelease:
+		return 0
+	case s.PreRelease == "":
+		return 1
+	case other.PreRelease == "":
+		return -1
+	}
+	a := strings.Split(s.PreRelease, ".")
+	b := strings.Split(other.PreRelease, ".")
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		// numeric identifiers are compared as numbers and are lower than alphanumeric ones
+		na, err_a := strconv.ParseUint(a[i], 10, 64)
+		nb, err_b := strconv.ParseUint(b[i], 10, 64)
+		switch {
+		case err_a == nil && err_b == nil && na < nb:
+			return -1
+		case err_a == nil && err_b == nil:
+			return 1
+		case err_a == nil:
+			return -1
+		case err_b == nil:
+			return 1
+		case a[i] < b[i]:
+			return -1
+		default:
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
 func (s *Int) GetReturnType() reflect.Type {
 	var i int
 	return reflect.TypeOf(i)
@@ -296,11 +465,132 @@ func (s *Octal) GetValue() interface{} {
 	return int(*s)
 }
 
+func (s *Uint) GetReturnType() reflect.Type {
+	var i uint
+	return reflect.TypeOf(i)
+}
+
+func (s *Uint) FromString(v string, fa IFlagArg) error {
+
+	i, err := strconv.ParseUint(v, 10, 0)
+	if err != nil {
+		return i18n.NewError("InvalidUintFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = Uint(i)
+	return nil
+}
+
+func (s *Uint) GetValue() interface{} {
+	return uint(*s)
+}
+
+func (s *Float) GetReturnType() reflect.Type {
+	var f float64
+	return reflect.TypeOf(f)
+}
+
+func (s *Float) FromString(v string, fa IFlagArg) error {
+
+	f, err := strconv.ParseFloat(v, 64)
+	// NaN and infinity are accepted by ParseFloat but are not meaningful values
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return i18n.NewError("InvalidFloatFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = Float(f)
+	return nil
+}
+
+func (s *Float) GetValue() interface{} {
+	return float64(*s)
+}
+
+// multipliers of size units, decimal units are powers of 1000 and binary units are powers of 1024
+var byteUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1e3,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1e6,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1e9,
+	"gib": 1 << 30,
+	"t":   1 << 40,
+	"tb":  1e12,
+	"tib": 1 << 40,
+	"p":   1 << 50,
+	"pb":  1e15,
+	"pib": 1 << 50,
+}
+
+func (s *Bytes) GetReturnType() reflect.Type {
+	var i int64
+	return reflect.TypeOf(i)
+}
+
+func (s *Bytes) FromString(v string, fa IFlagArg) error {
+
+	str := strings.TrimSpace(v)
+	// number is followed by optional unit
+	split := strings.IndexFunc(str, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split < 0 {
+		split = len(str)
+	}
+	n, err := strconv.ParseFloat(str[:split], 64)
+	multiplier, ok := byteUnits[strings.ToLower(strings.TrimSpace(str[split:]))]
+	// fraction of byte is rejected, fraction of larger unit is rounded to whole bytes
+	size := math.Round(n * multiplier)
+	if err != nil || !ok || (multiplier == 1 && n != math.Trunc(n)) || size >= math.MaxInt64 {
+		return i18n.NewError("InvalidBytesFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = Bytes(size)
+	return nil
+}
+
+func (s *Bytes) GetValue() interface{} {
+	return int64(*s)
+}
+
+func (s *Percent) GetReturnType() reflect.Type {
+	var f float64
+	return reflect.TypeOf(f)
+}
+
+func (s *Percent) FromString(v string, fa IFlagArg) error {
+
+	f, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "%"), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return i18n.NewError("InvalidPercentFormat", ElementTemplateContext{Element: fa, Extra: v})
+	}
+	*s = Percent(f)
+	return nil
+}
+
+func (s *Percent) GetValue() interface{} {
+	return float64(*s)
+}
+
 type IFlagArg interface {
 	IValidatable
 	GetUsage() string
 	GetDefault() string
 	GetHints() []string
+	GetEnum() IEnum
+	IsIgnoreCase() bool
+	IsSecret() bool
+	GetPathMode() PathMode
+	GetLayouts() []string
+	GetLocation() *time.Location
+	GetExtensions() []string
+	GetEnvVars() []string
+	GetCompleter() CompletionFunc
+	GetMin() string
+	GetMax() string
+	GetSource() ValueSource
 	IsCumulative() bool
 	GetValue() interface{}
 	SetByUser()
@@ -311,6 +601,8 @@ type IFlagArg interface {
 	Clear()
 	// private methodds
 	getDestination() interface{}
+	copyToField()
+	setSource(ValueSource)
 }
 
 type ICommand interface {
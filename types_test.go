@@ -1,7 +1,11 @@
 package gocli
 
 import (
+	"net"
+	"net/url"
+	"regexp"
 	"testing"
+	"time"
 )
 
 func TestTimeStamp_FromString(t *testing.T) {
@@ -324,3 +328,299 @@ func TestBinary_FromString(t *testing.T) {
 		})
 	}
 }
+
+func TestUint_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    uint
+		wantErr bool
+	}{
+		{name: "t1", v: "123", want: 123},
+		{name: "t2", v: "0", want: 0},
+		{name: "t3", v: "-1", wantErr: true},
+		{name: "t4", v: "1.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(Uint)
+			err := s.FromString(tt.v, &Flag[Uint]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Uint.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(uint) != tt.want {
+				t.Errorf("Uint.FromString() wrong value: wanted %d, got %d", tt.want, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestFloat_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    float64
+		wantErr bool
+	}{
+		{name: "t1", v: "1.5", want: 1.5},
+		{name: "t2", v: "-2", want: -2},
+		{name: "t3", v: "1e3", want: 1000},
+		{name: "t4", v: "abc", wantErr: true},
+		{name: "t5", v: "NaN", wantErr: true},
+		{name: "t6", v: "-Inf", wantErr: true},
+		{name: "t7", v: "1e400", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(Float)
+			err := s.FromString(tt.v, &Flag[Float]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Float.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(float64) != tt.want {
+				t.Errorf("Float.FromString() wrong value: wanted %v, got %v", tt.want, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestBytes_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    int64
+		wantErr bool
+	}{
+		{name: "plain", v: "512", want: 512},
+		{name: "bytes", v: "512B", want: 512},
+		{name: "decimal", v: "2GB", want: 2000000000},
+		{name: "binary", v: "10MiB", want: 10 * 1024 * 1024},
+		{name: "short", v: "4k", want: 4096},
+		{name: "fraction", v: "1.5KiB", want: 1536},
+		{name: "fraction rounded", v: "0.0001KB", want: 0},
+		{name: "decimal fraction", v: "1.1KB", want: 1100},
+		{name: "fraction of byte", v: "1.5", wantErr: true},
+		{name: "fraction of byte with unit", v: "1.5B", wantErr: true},
+		{name: "largest", v: "8191PiB", want: 8191 << 50},
+		{name: "overflow", v: "99999999999PiB", wantErr: true},
+		{name: "overflow at limit", v: "8192PiB", wantErr: true},
+		{name: "space and case", v: "3 mb", want: 3000000},
+		{name: "unknown unit", v: "10XB", wantErr: true},
+		{name: "negative", v: "-1MB", wantErr: true},
+		{name: "no number", v: "MB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(Bytes)
+			err := s.FromString(tt.v, &Flag[Bytes]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Bytes.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(int64) != tt.want {
+				t.Errorf("Bytes.FromString() wrong value: wanted %d, got %d", tt.want, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestPercent_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    float64
+		wantErr bool
+	}{
+		{name: "t1", v: "50%", want: 50},
+		{name: "t2", v: "12.5", want: 12.5},
+		{name: "t3", v: "%", wantErr: true},
+		{name: "t4", v: "half", wantErr: true},
+		{name: "t5", v: "nan%", wantErr: true},
+		{name: "t6", v: "+Inf", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(Percent)
+			err := s.FromString(tt.v, &Flag[Percent]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Percent.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(float64) != tt.want {
+				t.Errorf("Percent.FromString() wrong value: wanted %v, got %v", tt.want, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestURL_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		wantErr bool
+	}{
+		{name: "t1", v: "https://example.com/api?x=1"},
+		{name: "t2", v: "file:///tmp/data"},
+		{name: "t3", v: "example.com", wantErr: true},
+		{name: "t4", v: "http://[::1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(URL)
+			err := s.FromString(tt.v, &Flag[URL]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("URL.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(*url.URL).String() != tt.v {
+				t.Errorf("URL.FromString() wrong value: wanted %s, got %v", tt.v, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestRegexp_FromString(t *testing.T) {
+	s := new(Regexp)
+	if err := s.FromString(`^user-\d+$`, &Flag[Regexp]{Name: "test"}); err != nil {
+		t.Fatalf("Regexp.FromString() error = %v", err)
+	}
+	if re := s.GetValue().(*regexp.Regexp); !re.MatchString("user-12") || re.MatchString("user-x") {
+		t.Errorf("Regexp.FromString() wrong value %v", re)
+	}
+	if err := s.FromString(`user-(\d+`, &Flag[Regexp]{Name: "test"}); err == nil {
+		t.Errorf("Regexp.FromString() expected error for invalid expression")
+	}
+}
+
+func TestCIDR_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    string
+		wantErr bool
+	}{
+		{name: "t1", v: "10.0.0.0/8", want: "10.0.0.0/8"},
+		{name: "t2", v: "192.168.1.17/24", want: "192.168.1.0/24"},
+		{name: "t3", v: "2001:db8::/32", want: "2001:db8::/32"},
+		{name: "t4", v: "10.0.0.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(CIDR)
+			err := s.FromString(tt.v, &Flag[CIDR]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CIDR.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(*net.IPNet).String() != tt.want {
+				t.Errorf("CIDR.FromString() wrong value: wanted %s, got %v", tt.want, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestSemver_FromString(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    Semver
+		wantErr bool
+	}{
+		{name: "t1", v: "1.2.3", want: Semver{Major: 1, Minor: 2, Patch: 3}},
+		{name: "t2", v: "v0.10.0", want: Semver{Minor: 10}},
+		{name: "t3", v: "1.0.0-rc.1+build.5", want: Semver{Major: 1, PreRelease: "rc.1", Build: "build.5"}},
+		{name: "t4", v: "1.2", wantErr: true},
+		{name: "t5", v: "01.2.3", wantErr: true},
+		{name: "t6", v: "1.2.3-", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(Semver)
+			err := s.FromString(tt.v, &Flag[Semver]{Name: "test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Semver.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && s.GetValue().(Semver) != tt.want {
+				t.Errorf("Semver.FromString() wrong value: wanted %v, got %v", tt.want, s.GetValue())
+			}
+		})
+	}
+}
+
+func TestSemver_Compare(t *testing.T) {
+	// versions in increasing precedence
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0"}
+	versions := make([]Semver, len(ordered))
+	for i, v := range ordered {
+		if err := versions[i].FromString(v, &Flag[Semver]{Name: "test"}); err != nil {
+			t.Fatalf("Semver.FromString() error = %v", err)
+		}
+	}
+	for i := range versions {
+		for j := range versions {
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := versions[i].Compare(versions[j]); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", versions[i], versions[j], got, want)
+			}
+		}
+	}
+	if versions[7].String() != "1.0.0" || versions[6].String() != "1.0.0-rc.1" {
+		t.Errorf("Semver.String() wrong value %s, %s", versions[7], versions[6])
+	}
+}
+
+func TestTimeStamp_FromStringValues(t *testing.T) {
+	now := time.Date(2023, 6, 12, 15, 4, 5, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	ny, _ := time.LoadLocation("America/New_York")
+	if ny == nil {
+		ny = time.FixedZone("EST", -5*60*60)
+	}
+
+	tests := []struct {
+		name    string
+		v       string
+		fa      IFlagArg
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "day and month", v: "06/12/2023", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 6, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "date only", v: "2023-01-31", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "date and time", v: "2023-01-31 10:30", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{name: "time only is today", v: "04:00PM", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 6, 12, 16, 0, 0, 0, time.UTC)},
+		{name: "now", v: "now", fa: &Flag[TimeStamp]{Name: "t"}, want: now},
+		{name: "today", v: "today", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 6, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "yesterday", v: "Yesterday", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 6, 11, 0, 0, 0, 0, time.UTC)},
+		{name: "tomorrow", v: "tomorrow", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Date(2023, 6, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "minus duration", v: "-2h", fa: &Flag[TimeStamp]{Name: "t"}, want: now.Add(-2 * time.Hour)},
+		{name: "plus duration", v: "+1h30m", fa: &Flag[TimeStamp]{Name: "t"}, want: now.Add(90 * time.Minute)},
+		{name: "days ago", v: "3 days ago", fa: &Flag[TimeStamp]{Name: "t"}, want: now.AddDate(0, 0, -3)},
+		{name: "short unit ago", v: "10m ago", fa: &Flag[TimeStamp]{Name: "t"}, want: now.Add(-10 * time.Minute)},
+		{name: "in weeks", v: "in 2 weeks", fa: &Flag[TimeStamp]{Name: "t"}, want: now.AddDate(0, 0, 14)},
+		{name: "month ago", v: "1 month ago", fa: &Flag[TimeStamp]{Name: "t"}, want: now.AddDate(0, -1, 0)},
+		{name: "epoch", v: "1686582245", fa: &Flag[TimeStamp]{Name: "t"}, want: time.Unix(1686582245, 0).UTC()},
+		{name: "location", v: "2023-01-31 10:30", fa: &Flag[TimeStamp]{Name: "t", Location: ny}, want: time.Date(2023, 1, 31, 10, 30, 0, 0, ny)},
+		{name: "location of relative", v: "today", fa: &Flag[TimeStamp]{Name: "t", Location: ny}, want: time.Date(2023, 6, 12, 0, 0, 0, 0, ny)},
+		{name: "layouts", v: "31.01.2023", fa: &Flag[TimeStamp]{Name: "t", Layouts: []string{"02.01.2006"}}, want: time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "digits layout before epoch", v: "20230131", fa: &Flag[TimeStamp]{Name: "t", Layouts: []string{"20060102"}}, want: time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "epoch after layouts", v: "1686582245", fa: &Flag[TimeStamp]{Name: "t", Layouts: []string{"20060102"}}, want: time.Unix(1686582245, 0).UTC()},
+		{name: "layouts override defaults", v: "2023-01-31", fa: &Flag[TimeStamp]{Name: "t", Layouts: []string{"02.01.2006"}}, wantErr: true},
+		{name: "unknown unit", v: "3 fortnights ago", fa: &Flag[TimeStamp]{Name: "t"}, wantErr: true},
+		{name: "day first", v: "31/01/2023", fa: &Flag[TimeStamp]{Name: "t"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TimeStamp{}
+			err := s.FromString(tt.v, tt.fa)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TimeStamp.FromString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !s.GetValue().(time.Time).Equal(tt.want) {
+				t.Errorf("TimeStamp.FromString() = %v, want %v", s.GetValue(), tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package gocli
 
 import (
+	"fmt"
+	"os"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/ez-leka/gocli/i18n"
@@ -79,25 +82,31 @@ func mergeValidatables(g validationGroup) []IValidatable {
 }
 
 // set value that works for flags and arguments
-func inHints(hints []string, value string) string {
+func inHints(hints []string, value string, ignore_case bool) string {
 
+	equal := func(a string, b string) bool {
+		return a == b || (ignore_case && strings.EqualFold(a, b))
+	}
 	for _, h := range hints {
 		if strings.HasSuffix(h, "(s)") {
 			single := h[0 : len(h)-3]
 			plural := single + "s"
-			if value == single || value == plural {
+			if equal(value, single) || equal(value, plural) {
 				return single
 			}
 		} else {
-			if value == h {
-				return value
+			if equal(value, h) {
+				return h
 			}
 		}
 	}
 	return ""
 }
 
-func setFlagArgValue(fa IFlagArg, value string) error {
+func setFlagArgValue(fa IFlagArg, value string) (err error) {
+	// values of secret flags and arguments must not show up in errors
+	defer func() { err = redactError(fa, err) }()
+
 	dest := fa.getDestination()
 	rv := reflect.ValueOf(dest).Elem()
 
@@ -107,18 +116,56 @@ func setFlagArgValue(fa IFlagArg, value string) error {
 	}
 
 	cumulative := fa.IsCumulative()
-	if is_flag && !cumulative && fa.IsSetByUser() {
+	if is_flag && !cumulative && !fa.(IFlag).IsCounter() && fa.IsSetByUser() {
 		return i18n.NewError("FlagAlreadySet", fa)
 	}
 
-	if cumulative {
-		t := reflect.TypeOf(dest).Elem().Elem()
-		new_value := reflect.New(t).Interface()
-		err := new_value.(ISetable).FromString(value, fa)
-		if err != nil {
+	if enum := fa.GetEnum(); enum != nil && !isMap(fa) {
+		// only names of enum values are accepted, name is stored as defined in enum
+		name, _, ok := enum.Lookup(value, fa.IsIgnoreCase())
+		if !ok {
+			return i18n.NewError("UnknownEnumValue", ElementTemplateContext{Element: fa, Extra: value})
+		}
+		value = name
+	}
+
+	if isMap(fa) {
+		// map entry is key=value
+		kv := strings.SplitN(value, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return i18n.NewError("InvalidMapEntryFormat", ElementTemplateContext{Element: fa, Extra: value})
+		}
+		rt := reflect.TypeOf(dest).Elem()
+		key := reflect.New(rt.Key()).Interface()
+		if err := key.(ISetable).FromString(kv[0], fa); err != nil {
+			return err
+		}
+		elem := reflect.New(rt.Elem()).Interface()
+		if err := elem.(ISetable).FromString(kv[1], fa); err != nil {
 			return err
 		}
-		rv.Set(reflect.Append(rv, reflect.ValueOf(new_value).Elem()))
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rt))
+		}
+		rv.SetMapIndex(reflect.ValueOf(key).Elem(), reflect.ValueOf(elem).Elem())
+	} else if _, wrapped := dest.(cumulativeSetable); cumulative && !wrapped {
+		t := reflect.TypeOf(dest).Elem().Elem()
+		values := []string{value}
+		if expander, ok := reflect.New(t).Interface().(expandable); ok {
+			// glob pattern adds all matching paths
+			var err error
+			if values, err = expander.expand(value, fa); err != nil {
+				return err
+			}
+		}
+		for _, v := range values {
+			new_value := reflect.New(t).Interface()
+			err := new_value.(ISetable).FromString(v, fa)
+			if err != nil {
+				return err
+			}
+			rv.Set(reflect.Append(rv, reflect.ValueOf(new_value).Elem()))
+		}
 	} else {
 		setable := dest.(ISetable)
 		err := setable.FromString(value, fa)
@@ -127,6 +174,26 @@ func setFlagArgValue(fa IFlagArg, value string) error {
 		}
 	}
 
+	fa.copyToField()
+	return nil
+}
+
+// set value of flag or argument that was not set on command line from the first defined environment variable
+func setFlagArgFromEnv(fa IFlagArg) error {
+	if fa.IsSetByUser() {
+		return nil
+	}
+	for _, name := range fa.GetEnvVars() {
+		value, ok := os.LookupEnv(name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := fa.SetValue(value); err != nil {
+			return i18n.NewError("EnvVarValidationFailed", SourceTemplateContext{Element: fa, Key: name, Extra: redactValue(fa, value)})
+		}
+		fa.setSource(SourceEnvironment)
+		return nil
+	}
 	return nil
 }
 
@@ -137,7 +204,23 @@ func getFlagArgValue(fa IFlagArg) interface{} {
 	rt := reflect.TypeOf(dest).Elem()
 	rv := reflect.ValueOf(dest).Elem()
 
-	if fa.IsCumulative() {
+	if enum := fa.GetEnum(); enum != nil && !isMap(fa) {
+		return enumValue(fa, enum)
+	} else if isMap(fa) {
+		kt := reflect.New(rt.Key()).Interface().(ISetable).GetReturnType()
+		et := reflect.New(rt.Elem()).Interface().(ISetable).GetReturnType()
+		elemMap := reflect.MakeMapWithSize(reflect.MapOf(kt, et), rv.Len())
+
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := reflect.New(rt.Key())
+			k.Elem().Set(iter.Key())
+			e := reflect.New(rt.Elem())
+			e.Elem().Set(iter.Value())
+			elemMap.SetMapIndex(reflect.ValueOf(k.Interface().(ISetable).GetValue()), reflect.ValueOf(e.Interface().(ISetable).GetValue()))
+		}
+		return elemMap.Interface()
+	} else if _, wrapped := dest.(cumulativeSetable); fa.IsCumulative() && !wrapped {
 		tp := reflect.New(rt.Elem()).Interface().(ISetable).GetReturnType()
 		elemSlice := reflect.MakeSlice(reflect.SliceOf(tp), 0, 0)
 
@@ -151,13 +234,16 @@ func getFlagArgValue(fa IFlagArg) interface{} {
 	}
 }
 
-func isType[T TFlag | TArg](fa IFlagArg) bool {
+func isType[T any](fa IFlagArg) bool {
 
 	_, ok := any(fa.getDestination()).(*T)
 	return ok
 }
 
 func isCumulative(fa IFlagArg) bool {
+	if _, ok := fa.getDestination().(cumulativeSetable); ok {
+		return true
+	}
 	rt := reflect.TypeOf(fa.getDestination()).Elem()
 	switch rt.Kind() {
 	case reflect.Slice:
@@ -167,7 +253,202 @@ func isCumulative(fa IFlagArg) bool {
 			// thisis array of some primitive type - rnamed type
 			return false
 		}
+	case reflect.Map:
+		return isMap(fa)
 	default:
 		return false
 	}
 }
+
+// Go values of enum entries matching names stored in flag or argument
+func enumValue(fa IFlagArg, enum IEnum) interface{} {
+	lookup := func(setable ISetable) reflect.Value {
+		_, v, ok := enum.Lookup(fmt.Sprint(setable.GetValue()), false)
+		// nil value of interface or pointer typed enum has no reflect value
+		if !ok || v == nil {
+			return reflect.Zero(enum.GetValueType())
+		}
+		return reflect.ValueOf(v)
+	}
+
+	dest := fa.getDestination()
+	if !fa.IsCumulative() {
+		return lookup(dest.(ISetable)).Interface()
+	}
+	elements := setableElements(fa)
+	values := reflect.MakeSlice(reflect.SliceOf(enum.GetValueType()), 0, len(elements))
+	for _, setable := range elements {
+		values = reflect.Append(values, lookup(setable))
+	}
+	return values.Interface()
+}
+
+// checks that value of flag or argument is within Min and Max
+func checkRange(fa IFlagArg) error {
+	if (fa.GetMin() == "" && fa.GetMax() == "") || fa.GetSource() == SourceNone {
+		return nil
+	}
+
+	// bounds are parsed as single value of flag or argument
+	parseBound := func(bound string) (float64, bool, error) {
+		if bound == "" {
+			return 0, false, nil
+		}
+		setable := newSingleValue(fa).(ISetable)
+		if err := setable.FromString(bound, fa); err != nil {
+			return 0, false, err
+		}
+		n, ok := numericValue(setable.GetValue())
+		return n, ok, nil
+	}
+	min, has_min, err := parseBound(fa.GetMin())
+	if err != nil {
+		return err
+	}
+	max, has_max, err := parseBound(fa.GetMax())
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, 0)
+	rv := reflect.ValueOf(fa.GetValue())
+	switch rv.Kind() {
+	case reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i).Interface())
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			values = append(values, iter.Value().Interface())
+		}
+	default:
+		values = append(values, rv.Interface())
+	}
+
+	for _, v := range values {
+		n, ok := numericValue(v)
+		if !ok {
+			continue
+		}
+		if has_min && n < min {
+			return i18n.NewError("ValueBelowMin", ElementTemplateContext{Element: fa, Extra: redactValue(fa, fmt.Sprint(v))})
+		}
+		if has_max && n > max {
+			return i18n.NewError("ValueAboveMax", ElementTemplateContext{Element: fa, Extra: redactValue(fa, fmt.Sprint(v))})
+		}
+	}
+	return nil
+}
+
+// numeric value of int, uint or float for range comparison
+func numericValue(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// new single value of flag or argument, element of cumulative or map value
+func newSingleValue(fa IFlagArg) interface{} {
+	if wrapped, ok := fa.getDestination().(wrappedSetable); ok {
+		return wrapped.newElement()
+	}
+	rt := reflect.TypeOf(fa.getDestination()).Elem()
+	if isCumulative(fa) {
+		rt = rt.Elem()
+	}
+	return reflect.New(rt).Interface()
+}
+
+// values of cumulative flag or argument
+func setableElements(fa IFlagArg) []ISetable {
+	if cumulative, ok := fa.getDestination().(cumulativeSetable); ok {
+		return cumulative.elements()
+	}
+	rv := reflect.ValueOf(fa.getDestination()).Elem()
+	elements := make([]ISetable, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		elements = append(elements, rv.Index(i).Addr().Interface().(ISetable))
+	}
+	return elements
+}
+
+// name of element as user types it: --name for flags, placeholder for arguments
+func elementName(fa IFlagArg) string {
+	if _, ok := fa.(IFlag); ok {
+		return "--" + fa.GetName()
+	}
+	return fa.GetPlaceholder()
+}
+
+// map flags and arguments accumulate key=value entries
+func isMap(fa IFlagArg) bool {
+	rt := reflect.TypeOf(fa.getDestination()).Elem()
+	if rt.Kind() != reflect.Map {
+		return false
+	}
+	_, key_ok := reflect.New(rt.Key()).Interface().(ISetable)
+	_, elem_ok := reflect.New(rt.Elem()).Interface().(ISetable)
+	return key_ok && elem_ok
+}
+
+// edit distance between two strings
+func levenshtein(a string, b string) int {
+	ra := []rune(a)
+	rb := []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j] + 1
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+			if prev[j-1]+cost < curr[j] {
+				curr[j] = prev[j-1] + cost
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// candidates within max_distance edits from token, closest first
+func suggest(token string, candidates []string, max_distance int) []string {
+	if max_distance <= 0 {
+		return nil
+	}
+	distances := make(map[string]int)
+	for _, c := range candidates {
+		if d := levenshtein(token, c); d <= max_distance {
+			distances[c] = d
+		}
+	}
+	suggestions := make([]string, 0, len(distances))
+	for c := range distances {
+		suggestions = append(suggestions, c)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	return suggestions
+}
@@ -10,7 +10,7 @@ import (
 func Test_getFlagArgValue(t *testing.T) {
 	IP_str := "10.100.10.10"
 
-	tm, _ := time.Parse("01/01/2006 03:04:05 PM", "06/12/2023 03:04:05 PM")
+	tm, _ := time.Parse("01/02/2006 03:04:05 PM", "06/12/2023 03:04:05 PM")
 
 	ip := net.ParseIP(IP_str)
 
@@ -99,9 +99,19 @@ func Test_getFlagArgValue(t *testing.T) {
 				fa: &Flag[File]{
 					Name: "f1",
 				},
-				value: "./*.go",
+				value: "./util?.go",
 			},
-			want: "./*.go",
+			want: "utils.go",
+		},
+		{
+			name: "[]File",
+			args: args{
+				fa: &Flag[[]File]{
+					Name: "f1",
+				},
+				value: "./utils*.go,./types.go",
+			},
+			want: []string{"utils.go", "utils_test.go", "./types.go"},
 		},
 		{
 			name: "IP",
@@ -123,6 +133,26 @@ func Test_getFlagArgValue(t *testing.T) {
 			},
 			want: []net.IP{ip},
 		},
+		{
+			name: "[]Semver",
+			args: args{
+				fa: &Flag[[]Semver]{
+					Name: "f1",
+				},
+				value: "1.2.3,v2.0.0-rc.1",
+			},
+			want: []Semver{{Major: 1, Minor: 2, Patch: 3}, {Major: 2, PreRelease: "rc.1"}},
+		},
+		{
+			name: "map[String]Int",
+			args: args{
+				fa: &Flag[map[String]Int]{
+					Name: "f1",
+				},
+				value: "a=1,b=2",
+			},
+			want: map[string]int{"a": 1, "b": 2},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -134,3 +164,26 @@ func Test_getFlagArgValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_suggest(t *testing.T) {
+	candidates := []string{"deploy", "delete", "describe", "status"}
+	tests := []struct {
+		token    string
+		distance int
+		want     []string
+	}{
+		{"deploy", 2, []string{"deploy"}},
+		{"delpoy", 2, []string{"deploy"}},
+		{"deletx", 2, []string{"delete"}},
+		{"dele", 2, []string{"delete"}},
+		{"xyz", 2, []string{}},
+		{"delpoy", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			if got := suggest(tt.token, candidates, tt.distance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package gocli
+
+import "reflect"
+
+// Value is value type of flag or argument of user defined type. P is pointer to the type and must implement ISetable,
+// i.e. Flag[Value[*Color]]. GetValue of flag or argument returns GetValue of P
+type Value[P ISetable] struct {
+	value ISetable
+}
+
+// Values is cumulative value type of flag or argument of user defined type, i.e. Flag[Values[*Color]].
+// GetValue of flag or argument returns slice of GetValue of every P
+type Values[P ISetable] struct {
+	values []ISetable
+}
+
+// implemented by pointers to Value and Values to create single value of user defined type
+type wrappedSetable interface {
+	newElement() ISetable
+}
+
+// implemented by pointer to Values, FromString adds value
+type cumulativeSetable interface {
+	wrappedSetable
+	elements() []ISetable
+}
+
+// new value of type P; pointer type is allocated, other types are zero
+func newSetable[P ISetable]() ISetable {
+	rt := reflect.TypeOf((*P)(nil)).Elem()
+	if rt.Kind() == reflect.Ptr {
+		return reflect.New(rt.Elem()).Interface().(ISetable)
+	}
+	return reflect.Zero(rt).Interface().(ISetable)
+}
+
+// NewValue returns Value bound to p, use it as Destination to have value of flag or argument stored in p
+func NewValue[P ISetable](p P) *Value[P] {
+	return &Value[P]{value: p}
+}
+
+// Get returns value of type P
+func (v *Value[P]) Get() P {
+	if v.value == nil {
+		v.value = newSetable[P]()
+	}
+	return v.value.(P)
+}
+
+func (v *Value[P]) GetReturnType() reflect.Type {
+	return newSetable[P]().GetReturnType()
+}
+
+func (v *Value[P]) FromString(s string, fa IFlagArg) error {
+	return v.Get().FromString(s, fa)
+}
+
+func (v *Value[P]) GetValue() interface{} {
+	return v.Get().GetValue()
+}
+
+func (v *Value[P]) newElement() ISetable {
+	return newSetable[P]()
+}
+
+// Get returns values of type P in order they were set
+func (v *Values[P]) Get() []P {
+	values := make([]P, 0, len(v.values))
+	for _, value := range v.values {
+		values = append(values, value.(P))
+	}
+	return values
+}
+
+func (v *Values[P]) GetReturnType() reflect.Type {
+	return reflect.SliceOf(newSetable[P]().GetReturnType())
+}
+
+func (v *Values[P]) FromString(s string, fa IFlagArg) error {
+	value := newSetable[P]()
+	if err := value.FromString(s, fa); err != nil {
+		return err
+	}
+	v.values = append(v.values, value)
+	return nil
+}
+
+func (v *Values[P]) GetValue() interface{} {
+	values := reflect.MakeSlice(v.GetReturnType(), 0, len(v.values))
+	for _, value := range v.values {
+		item := reflect.Zero(values.Type().Elem())
+		if value.GetValue() != nil {
+			item = reflect.ValueOf(value.GetValue())
+		}
+		values = reflect.Append(values, item)
+	}
+	return values.Interface()
+}
+
+func (v *Values[P]) newElement() ISetable {
+	return newSetable[P]()
+}
+
+func (v *Values[P]) elements() []ISetable {
+	return v.values
+}